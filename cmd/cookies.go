@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/icon"
+	"github.com/preetbiswas12/Kage/network"
+	"github.com/preetbiswas12/Kage/style"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+func init() {
+	rootCmd.AddCommand(cookiesCmd)
+}
+
+var cookiesCmd = &cobra.Command{
+	Use:   "cookies",
+	Short: "Manage cookies used by the sources",
+	Long: `Manage cookies that are sent with the requests of all the sources.
+
+Cookies set by the websites are saved and reused between runs.
+Cookies obtained in a browser, such as Cloudflare clearance ones,
+can be imported from a cookies.txt file in the Netscape format,
+which is exported by most browser extensions.
+
+Note that some websites bind the cookies to the User-Agent of the browser.
+
+SUBCOMMANDS:
+  cookies import  Import cookies from a cookies.txt file
+  cookies list    Show stored cookies
+  cookies clear   Remove stored cookies`,
+}
+
+func init() {
+	cookiesCmd.AddCommand(cookiesImportCmd)
+}
+
+var cookiesImportCmd = &cobra.Command{
+	Use:   "import <cookies.txt>",
+	Short: "Import cookies from a cookies.txt file",
+	Args:  cobra.ExactArgs(1),
+	Example: `  # Import cookies exported from the browser
+  kage cookies import ~/Downloads/cookies.txt`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, err := filesystem.Api().Open(args[0])
+		handleErr(err)
+		defer util.Ignore(file.Close)
+
+		imported, err := network.ImportCookies(file)
+		handleErr(err)
+
+		cmd.Printf("%s %s imported\n", icon.Get(icon.Success), util.Quantify(imported, "cookie", "cookies"))
+	},
+}
+
+func init() {
+	cookiesCmd.AddCommand(cookiesListCmd)
+
+	cookiesListCmd.Flags().StringP("domain", "d", "", "show only cookies of the domain and its subdomains")
+	cookiesListCmd.Flags().BoolP("values", "v", false, "show cookie values")
+	cookiesListCmd.SetOut(os.Stdout)
+}
+
+var cookiesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show stored cookies",
+	Run: func(cmd *cobra.Command, args []string) {
+		var (
+			cookies    = network.Cookies(lo.Must(cmd.Flags().GetString("domain")))
+			showValues = lo.Must(cmd.Flags().GetBool("values"))
+			domain     string
+		)
+
+		if len(cookies) == 0 {
+			cmd.Println("No cookies stored")
+			return
+		}
+
+		for _, cookie := range cookies {
+			if cookie.Domain != domain {
+				domain = cookie.Domain
+				cmd.Println(style.Bold(domain))
+			}
+
+			expires := "session"
+			if !cookie.Expires.IsZero() {
+				expires = "expires " + cookie.Expires.Format("2006-01-02 15:04")
+			}
+
+			if showValues {
+				cmd.Printf("  %s=%s %s\n", cookie.Name, cookie.Value, style.Faint(expires))
+			} else {
+				cmd.Printf("  %s %s\n", cookie.Name, style.Faint(expires))
+			}
+		}
+	},
+}
+
+func init() {
+	cookiesCmd.AddCommand(cookiesClearCmd)
+
+	cookiesClearCmd.Flags().StringP("domain", "d", "", "remove only cookies of the domain and its subdomains")
+}
+
+var cookiesClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove stored cookies",
+	Example: `  # Remove all cookies
+  kage cookies clear
+
+  # Remove cookies of a single website
+  kage cookies clear --domain mangapill.com`,
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := network.ClearCookies(lo.Must(cmd.Flags().GetString("domain")))
+		handleErr(err)
+
+		cmd.Printf("%s %s removed\n", icon.Get(icon.Success), util.Quantify(removed, "cookie", "cookies"))
+	},
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -30,6 +31,10 @@ func init() {
 	rootCmd.AddCommand(inlineCmd)
 
 	inlineCmd.Flags().StringP("query", "q", "", "query to search for")
+	inlineCmd.Flags().Int("search-pages", 1, "number of search result pages to load from each source, 0 to load all")
+	inlineCmd.Flags().StringArray("filter", nil, "search filter in the key=value form, can be repeated")
+	inlineCmd.Flags().String("browse", "", "browse mangas without a query: latest, popular or recent")
+	inlineCmd.Flags().Bool("aggregate", false, "merge the same mangas found in several sources and rank them by relevance to the query")
 	inlineCmd.Flags().StringP("manga", "m", "", "manga selector")
 	inlineCmd.Flags().StringP("chapters", "c", "", "chapter selector")
 	inlineCmd.Flags().BoolP("download", "d", false, "download chapters")
@@ -41,13 +46,20 @@ func init() {
 
 	inlineCmd.Flags().StringP("output", "o", "", "output file")
 
-	lo.Must0(inlineCmd.MarkFlagRequired("query"))
+	inlineCmd.MarkFlagsMutuallyExclusive("query", "browse")
+	inlineCmd.MarkFlagsMutuallyExclusive("filter", "browse")
 	inlineCmd.MarkFlagsMutuallyExclusive("download", "json")
 	inlineCmd.MarkFlagsMutuallyExclusive("include-anilist-manga", "download")
 
 	inlineCmd.RegisterFlagCompletionFunc("query", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return query.SuggestMany(toComplete), cobra.ShellCompDirectiveNoFileComp
 	})
+
+	inlineCmd.RegisterFlagCompletionFunc("browse", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return lo.Map(source.BrowseLists, func(list source.BrowseList, _ int) string {
+			return string(list)
+		}), cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 var inlineCmd = &cobra.Command{
@@ -60,7 +72,10 @@ using command-line arguments. Perfect for scripts, batch processing, and getting
 JSON output for integration with other tools.
 
 FLAGS:
-  -q, --query STRING      Search query (required). Example: "Death Note"
+  -q, --query STRING      Search query (required unless browsing). Example: "Death Note"
+      --browse STRING     Browse mangas without a query: latest, popular, recent
+      --search-pages INT  Search result pages to load per source, 0 for all (default: 1)
+      --filter KEY=VALUE  Search filter, can be repeated. Example: --filter status=ongoing
   -m, --manga STRING      Manga selector: first, last, [number], @[substring]@
   -c, --chapter STRING    Chapter selector: first, last, all, [number], [from]-[to]
   -d, --download          Download selected chapters
@@ -100,6 +115,12 @@ EXAMPLES:
   # Use specific source for faster results
   kage inline -q "Naruto" -S Mangadex -m first -c all -d
   
+  # Search only ongoing manga with the given tag
+  kage inline -q "Naruto" -S Mangadex -j --filter status=ongoing --filter tags=action --filter tags=comedy
+
+  # List latest updates of Mangadex
+  kage inline --browse latest -S Mangadex -j
+
   # JSON output to file for processing
   kage inline -q "Bleach" -j -o results.json
   
@@ -130,6 +151,14 @@ For more examples: https://github.com/preetbiswas12/Kage/wiki/Inline-mode`,
 
 More examples: https://github.com/preetbiswas12/Kage/wiki/Inline-mode`,
 	PreRun: func(cmd *cobra.Command, args []string) {
+		if !cmd.Flags().Changed("query") && !cmd.Flags().Changed("browse") {
+			handleErr(errors.New("query or browse flag is required"))
+		}
+
+		if browse := lo.Must(cmd.Flags().GetString("browse")); browse != "" && !lo.Contains(source.BrowseLists, source.BrowseList(browse)) {
+			handleErr(fmt.Errorf("unknown browse list: %s", browse))
+		}
+
 		json, _ := cmd.Flags().GetBool("json")
 
 		if !json {
@@ -193,11 +222,18 @@ More examples: https://github.com/preetbiswas12/Kage/wiki/Inline-mode`,
 			chapterFilter = mo.Some(fn)
 		}
 
+		filters, err := inline.ParseFilters(lo.Must(cmd.Flags().GetStringArray("filter")))
+		handleErr(err)
+
 		options := &inline.Options{
 			Sources:             sources,
 			Download:            lo.Must(cmd.Flags().GetBool("download")),
 			Json:                lo.Must(cmd.Flags().GetBool("json")),
 			Query:               query,
+			SearchPages:         lo.Must(cmd.Flags().GetInt("search-pages")),
+			Filters:             filters,
+			Browse:              source.BrowseList(lo.Must(cmd.Flags().GetString("browse"))),
+			Aggregate:           lo.Must(cmd.Flags().GetBool("aggregate")),
 			PopulatePages:       lo.Must(cmd.Flags().GetBool("populate-pages")),
 			IncludeAnilistManga: lo.Must(cmd.Flags().GetBool("include-anilist-manga")),
 			MangaPicker:         mangaPicker,
@@ -205,7 +241,15 @@ More examples: https://github.com/preetbiswas12/Kage/wiki/Inline-mode`,
 			Out:                 writer,
 		}
 
-		handleErr(inline.Run(options))
+		// cancel searching and downloading on interrupt, a second one kills the process
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+
+		handleErr(inline.Run(ctx, options))
 	},
 }
 
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"os"
+	"os/signal"
+
 	"github.com/preetbiswas12/Kage/converter"
 	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/mini"
@@ -50,7 +53,15 @@ FLAGS:
 			Download: lo.Must(cmd.Flags().GetBool("download")),
 			Continue: lo.Must(cmd.Flags().GetBool("continue")),
 		}
-		err := mini.Run(&options)
+		// cancel searching and downloading on interrupt, a second one kills the process
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+
+		err := mini.Run(ctx, &options)
 
 		if err != nil && err.Error() != "interrupt" {
 			handleErr(err)
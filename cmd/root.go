@@ -55,6 +55,9 @@ func init() {
 	}))
 	lo.Must0(viper.BindPFlag(key.DownloaderDefaultSources, rootCmd.PersistentFlags().Lookup("source")))
 
+	rootCmd.PersistentFlags().Bool("refresh", false, "ignore the cached pages of the scrapers")
+	lo.Must0(viper.BindPFlag(key.CacheRefresh, rootCmd.PersistentFlags().Lookup("refresh")))
+
 	rootCmd.Flags().BoolP("continue", "c", false, "continue reading")
 
 	helpFunc := rootCmd.HelpFunc()
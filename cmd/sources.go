@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/preetbiswas12/Kage/color"
 	"github.com/preetbiswas12/Kage/constant"
 	"github.com/preetbiswas12/Kage/key"
@@ -17,7 +21,10 @@ import (
 
 	"github.com/preetbiswas12/Kage/filesystem"
 	"github.com/preetbiswas12/Kage/icon"
+	"github.com/preetbiswas12/Kage/probe"
 	"github.com/preetbiswas12/Kage/provider"
+	"github.com/preetbiswas12/Kage/provider/generic"
+	"github.com/preetbiswas12/Kage/provider/local"
 	"github.com/preetbiswas12/Kage/style"
 	"github.com/preetbiswas12/Kage/where"
 	"github.com/samber/lo"
@@ -37,10 +44,13 @@ Built-in sources:
   • Mangadex (API-based) - Fast, reliable, comprehensive
   • Mangapill (HTML scraping) - Alternative source
 
-Custom sources can be written in Lua for additional manga websites.
+Custom sources can be written in Lua for additional manga websites,
+or described with CSS selectors in YAML or JSON files for simple ones.
 
 SUBCOMMANDS:
   sources list    Show all available sources
+  sources test    Check that sources work
+  sources cache   Show or clear cached pages of the scrapers
   sources install Browse and install custom Lua scrapers
   sources remove  Remove custom sources
   sources gen     Generate a new Lua scraper template`,
@@ -119,7 +129,7 @@ func init() {
 
 		return lo.FilterMap(sources, func(item os.FileInfo, _ int) (string, bool) {
 			name := item.Name()
-			if !strings.HasSuffix(name, provider.CustomProviderExtension) {
+			if !lo.Contains(provider.CustomProviderExtensions, filepath.Ext(name)) {
 				return "", false
 			}
 
@@ -139,7 +149,17 @@ var sourcesRemoveCmd = &cobra.Command{
   mangal sources remove -n source1 -n source2`,
 	Run: func(cmd *cobra.Command, args []string) {
 		for _, name := range lo.Must(cmd.Flags().GetStringArray("name")) {
-			path := filepath.Join(where.Sources(), name+provider.CustomProviderExtension)
+			path, ok := lo.Find(lo.Map(provider.CustomProviderExtensions, func(extension string, _ int) string {
+				return filepath.Join(where.Sources(), name+extension)
+			}), func(path string) bool {
+				exists, _ := filesystem.Api().Exists(path)
+				return exists
+			})
+
+			if !ok {
+				handleErr(fmt.Errorf("source not found: %s", name))
+			}
+
 			handleErr(filesystem.Api().Remove(path))
 			fmt.Printf("%s successfully removed %s\n", icon.Get(icon.Success), style.Fg(color.Yellow)(name))
 		}
@@ -233,3 +253,217 @@ includes function stubs for searching, listing chapters, and fetching pages.`,
 		cmd.Println(target)
 	},
 }
+
+func init() {
+	sourcesCmd.AddCommand(sourcesTestCmd)
+
+	sourcesTestCmd.Flags().StringP("query", "q", "one", "query to search for")
+	sourcesTestCmd.Flags().StringArrayP("name", "n", []string{}, "name of the source to test")
+	sourcesTestCmd.Flags().BoolP("custom", "c", false, "test only custom sources")
+	sourcesTestCmd.Flags().BoolP("builtin", "b", false, "test only builtin sources")
+	sourcesTestCmd.Flags().BoolP("json", "j", false, "output as JSON")
+
+	sourcesTestCmd.MarkFlagsMutuallyExclusive("custom", "builtin")
+	sourcesTestCmd.SetOut(os.Stdout)
+}
+
+var sourcesTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Check that manga sources work",
+	Long: `Check that manga sources still work, e.g. after the website has changed.
+
+Each source searches for the query, lists chapters of the found manga,
+lists pages of its first chapter and downloads the first page.
+Stages after the first failed one are skipped.
+Exits with a non-zero code if any source fails.`,
+	Example: `  # Test all sources
+  mangal sources test
+
+  # Test a single source with a custom query
+  mangal sources test -n Mangadex -q "one piece"
+
+  # Output results as JSON
+  mangal sources test --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var providers []*provider.Provider
+
+		switch {
+		case lo.Must(cmd.Flags().GetBool("builtin")):
+			providers = provider.Builtins()
+		case lo.Must(cmd.Flags().GetBool("custom")):
+			providers = provider.Customs()
+		default:
+			providers = append(provider.Builtins(), provider.Customs()...)
+		}
+
+		if names := lo.Must(cmd.Flags().GetStringArray("name")); len(names) > 0 {
+			for _, name := range names {
+				if _, ok := lo.Find(providers, func(p *provider.Provider) bool {
+					return strings.EqualFold(p.Name, name)
+				}); !ok {
+					handleErr(fmt.Errorf("source not found: %s", name))
+				}
+			}
+
+			providers = lo.Filter(providers, func(p *provider.Provider, _ int) bool {
+				return lo.ContainsBy(names, func(name string) bool {
+					return strings.EqualFold(p.Name, name)
+				})
+			})
+		} else {
+			// local library has nothing to do with the network, test it only when asked
+			providers = lo.Filter(providers, func(p *provider.Provider, _ int) bool {
+				return p.ID != local.ID
+			})
+		}
+
+		// cancel probing on interrupt, a second one kills the process
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			stop()
+		}()
+
+		var (
+			query   = lo.Must(cmd.Flags().GetString("query"))
+			asJson  = lo.Must(cmd.Flags().GetBool("json"))
+			reports = make([]*probe.Report, 0, len(providers))
+		)
+
+		for _, p := range providers {
+			report := probe.Run(ctx, p, query)
+			reports = append(reports, report)
+
+			if !asJson {
+				printProbeReport(cmd, report)
+			}
+		}
+
+		if asJson {
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+			lo.Must0(encoder.Encode(reports))
+		}
+
+		if lo.SomeBy(reports, func(report *probe.Report) bool {
+			return !report.Passed
+		}) {
+			os.Exit(1)
+		}
+	},
+}
+
+// printProbeReport prints the report of the source probe in the human-readable format
+func printProbeReport(cmd *cobra.Command, report *probe.Report) {
+	var status string
+	if report.Passed {
+		status = style.Fg(color.Green)(icon.Get(icon.Success))
+	} else {
+		status = style.Fg(color.Red)(icon.Get(icon.Fail))
+	}
+
+	cmd.Printf("%s %s\n", status, style.Bold(report.Source))
+
+	for _, stage := range report.Stages {
+		switch {
+		case stage.Skipped:
+			cmd.Printf("  %s %s\n", style.Faint("-"), style.Faint(stage.Name+" skipped"))
+		case stage.Passed:
+			cmd.Printf(
+				"  %s %-8s %s %s\n",
+				style.Fg(color.Green)(icon.Get(icon.Success)),
+				stage.Name,
+				style.Faint(stage.Duration.Round(time.Millisecond).String()),
+				stage.Summary,
+			)
+		default:
+			cmd.Printf(
+				"  %s %-8s %s %s\n",
+				style.Fg(color.Red)(icon.Get(icon.Fail)),
+				stage.Name,
+				style.Faint(stage.Duration.Round(time.Millisecond).String()),
+				style.Fg(color.Red)(stage.Error),
+			)
+		}
+	}
+
+	cmd.Println()
+}
+
+func init() {
+	sourcesCmd.AddCommand(sourcesCacheCmd)
+
+	sourcesCacheCmd.Flags().StringArrayP("clear", "x", []string{}, "name of the source to clear the cache of")
+	sourcesCacheCmd.Flags().Bool("clear-all", false, "clear the cache of all the sources")
+
+	sourcesCacheCmd.MarkFlagsMutuallyExclusive("clear", "clear-all")
+	sourcesCacheCmd.SetOut(os.Stdout)
+}
+
+var sourcesCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Show or clear cached pages of the scrapers",
+	Long: `Show statistics of the pages cached by the scrapers, such as Mangapill and declarative sources.
+
+Search results, chapter lists and page lists are cached separately,
+each for the time set by the cache.*_lifetime config keys.
+Expired pages are requested again when needed.
+Use --refresh with any command to ignore the cache once.`,
+	Example: `  # Show cache statistics
+  mangal sources cache
+
+  # Clear the cache of a single source
+  mangal sources cache --clear Mangapill`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if lo.Must(cmd.Flags().GetBool("clear-all")) {
+			stats, err := generic.ReadCacheStats()
+			handleErr(err)
+
+			for _, name := range lo.Uniq(lo.Map(stats, func(stat *generic.CacheStats, _ int) string {
+				return stat.Source
+			})) {
+				handleErr(generic.InvalidateCache(name))
+			}
+
+			cmd.Printf("%s Cache cleared\n", icon.Get(icon.Success))
+			return
+		}
+
+		if names := lo.Must(cmd.Flags().GetStringArray("clear")); len(names) > 0 {
+			for _, name := range names {
+				handleErr(generic.InvalidateCache(name))
+				cmd.Printf("%s %s cache cleared\n", icon.Get(icon.Success), name)
+			}
+
+			return
+		}
+
+		stats, err := generic.ReadCacheStats()
+		handleErr(err)
+
+		if len(stats) == 0 {
+			cmd.Println("Nothing is cached")
+			return
+		}
+
+		var source string
+		for _, stat := range stats {
+			if stat.Source != source {
+				if source != "" {
+					cmd.Println()
+				}
+
+				source = stat.Source
+				cmd.Println(style.Bold(source))
+			}
+
+			cmd.Printf(
+				"  %-8s %s %s\n",
+				stat.Stage,
+				util.Quantify(stat.Entries, "page", "pages"),
+				style.Faint(fmt.Sprintf("%d expired, %s", stat.Expired, humanize.Bytes(uint64(stat.Size)))),
+			)
+		}
+	},
+}
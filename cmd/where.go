@@ -28,6 +28,7 @@ var wherePaths = []*whereTarget{
 	{"Cache", where.Cache, "cache", mo.None[string](), true},
 	{"Temp", where.Temp, "temp", mo.None[string](), true},
 	{"History", where.History, "history", mo.None[string](), true},
+	{"Cookies", where.Cookies, "cookies", mo.None[string](), true},
 }
 
 func init() {
@@ -6,6 +6,22 @@ const (
 	ChapterPagesFn  = "ChapterPages"
 )
 
+// Optional functions that sources may define
+const (
+	// SearchMangaPageFn is called with the query and the page number (starting from 1).
+	// Empty table means there are no more pages.
+	SearchMangaPageFn = "SearchMangaPage"
+	// SearchFiltersFn returns the table of filters that the source supports.
+	// Chosen filters are then passed to the search functions as the last argument.
+	SearchFiltersFn = "SearchFilters"
+	// BrowseMangaFn is called with the list name ("latest", "popular" or "recent")
+	// and the page number (starting from 1). Empty table means there are no more pages.
+	BrowseMangaFn = "BrowseManga"
+	// MangaDetailsFn is called with the manga URL and returns a table with the manga details:
+	// summary, status, cover and comma separated authors, artists, genres and tags
+	MangaDetailsFn = "MangaDetails"
+)
+
 const SourceTemplate = `{{ $divider := repeat "-" (plus (max (len .URL) (len .Name) (len .Author) 3) 12) }}{{ $divider }}
 -- @name    {{ .Name }} 
 -- @url     {{ .URL }}
@@ -15,8 +31,9 @@ const SourceTemplate = `{{ $divider := repeat "-" (plus (max (len .URL) (len .Na
 
 
 ---@alias manga { name: string, url: string, author: string|nil, genres: string|nil, summary: string|nil }
----@alias chapter { name: string, url: string, volume: string|nil, manga_summary: string|nil, manga_author: string|nil, manga_genres: string|nil }
+---@alias chapter { name: string, url: string, volume: string|nil, number: string|number|nil, groups: string|nil, language: string|nil, date: string|nil, pages_count: number|nil, manga_summary: string|nil, manga_author: string|nil, manga_genres: string|nil }
 ---@alias page { url: string, index: number }
+---@alias filter { key: string, name: string|nil, multiple: boolean|nil, options: (string|{ name: string|nil, value: string })[]|nil }
 
 
 ----- IMPORTS -----
@@ -34,8 +51,9 @@ const SourceTemplate = `{{ $divider := repeat "-" (plus (max (len .URL) (len .Na
 
 --- Searches for manga with given query.
 -- @param query string Query to search for
+-- @param filters table|nil Chosen filters, if the source defines SearchFilters function
 -- @return manga[] Table of mangas
-function {{ .SearchMangaFn }}(query)
+function {{ .SearchMangaFn }}(query, filters)
 	return {}
 end
 
@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/preetbiswas12/Kage/color"
@@ -18,6 +19,12 @@ import (
 
 // Download the chapter using given source.
 func Download(chapter *source.Chapter, progress func(string)) (string, error) {
+	return DownloadContext(context.Background(), chapter, progress)
+}
+
+// DownloadContext downloads the chapter using given source.
+// Downloading is stopped once the context is done.
+func DownloadContext(ctx context.Context, chapter *source.Chapter, progress func(string)) (string, error) {
 	log.Info("downloading " + chapter.Name)
 
 	path, err := chapter.Path(false)
@@ -25,6 +32,12 @@ func Download(chapter *source.Chapter, progress func(string)) (string, error) {
 		return "", err
 	}
 
+	// the chapter file is the one the pages are read from, it must not be replaced
+	if _, ok := chapter.Source().(source.LocalSource); ok {
+		log.Info("chapter is stored locally, skipping")
+		return path, nil
+	}
+
 	if viper.GetBool(key.DownloaderRedownloadExisting) {
 		log.Info("chapter already downloaded, deleting and redownloading")
 		err = filesystem.Api().Remove(path)
@@ -40,24 +53,31 @@ func Download(chapter *source.Chapter, progress func(string)) (string, error) {
 	}
 
 	progress("Getting pages")
-	pages, err := chapter.Source().PagesOf(chapter)
-	if err != nil {
-		log.Error(err)
-		return "", err
+	pages, err := source.PagesOf(ctx, chapter.Source(), chapter)
+	if err == nil {
+		log.Info("found " + fmt.Sprintf("%d", len(pages)) + " pages")
+		err = chapter.DownloadPagesContext(ctx, false, progress)
 	}
-	log.Info("found " + fmt.Sprintf("%d", len(pages)) + " pages")
 
-	err = chapter.DownloadPages(false, progress)
 	if err != nil {
 		log.Error(err)
-		return "", err
-	}
 
-	if viper.GetBool(key.MetadataFetchAnilist) {
-		err := chapter.Manga.PopulateMetadata(progress)
-		if err != nil {
-			log.Warn(err)
+		// retries are exhausted by now, try the same chapter of other sources
+		if ctx.Err() != nil || len(viper.GetStringSlice(key.DownloaderFallbackSources)) == 0 {
+			return "", err
+		}
+
+		if fallbackErr := downloadFallback(ctx, chapter, progress); fallbackErr != nil {
+			log.Error(fallbackErr)
+			return "", err
 		}
+
+		pages = chapter.Pages
+	}
+
+	// source details are always fetched, anilist ones only if enabled
+	if err := chapter.Manga.PopulateMetadataContext(ctx, progress); err != nil {
+		log.Warn(err)
 	}
 
 	if viper.GetBool(key.MetadataSeriesJSON) {
@@ -83,7 +103,7 @@ func Download(chapter *source.Chapter, progress func(string)) (string, error) {
 	if viper.GetBool(key.DownloaderDownloadCover) {
 		coverDir, err := chapter.Manga.Path(false)
 		if err == nil {
-			_ = chapter.Manga.DownloadCover(false, coverDir, progress)
+			_ = chapter.Manga.DownloadCoverContext(ctx, false, coverDir, progress)
 		}
 	}
 
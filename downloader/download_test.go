@@ -0,0 +1,68 @@
+package downloader
+
+import (
+	"errors"
+	"github.com/preetbiswas12/Kage/config"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"testing"
+)
+
+func init() {
+	filesystem.SetMemMapFs()
+	lo.Must0(config.Setup())
+}
+
+// localSource reads the chapters from the files they are stored in
+type localSource struct{}
+
+func (localSource) Name() string { return "Local" }
+
+func (localSource) ID() string { return "local" }
+
+func (localSource) Search(string) ([]*source.Manga, error) { return nil, nil }
+
+func (localSource) ChaptersOf(*source.Manga) ([]*source.Chapter, error) { return nil, nil }
+
+func (localSource) PagesOf(*source.Chapter) ([]*source.Page, error) {
+	return nil, errors.New("chapter file is missing")
+}
+
+func (localSource) ChapterPath(chapter *source.Chapter) string { return chapter.URL }
+
+func TestDownload_LocalSource(t *testing.T) {
+	Convey("Given a chapter of the local library", t, func() {
+		filesystem.SetMemMapFs()
+		path := "/downloads/One Piece/Romance Dawn.cbz"
+		So(filesystem.Api().MkdirAll("/downloads/One Piece", 0755), ShouldBeNil)
+		So(filesystem.Api().WriteFile(path, []byte("chapter"), 0644), ShouldBeNil)
+
+		manga := &source.Manga{Name: "One Piece", URL: "/downloads/One Piece", Source: localSource{}}
+		chapter := &source.Chapter{Name: "Romance Dawn", URL: path, Index: 1, Manga: manga}
+		manga.Chapters = []*source.Chapter{chapter}
+
+		Convey("When downloading it with redownloading enabled", func() {
+			viper.Set(key.DownloaderRedownloadExisting, true)
+			defer viper.Set(key.DownloaderRedownloadExisting, false)
+
+			downloaded, err := Download(chapter, func(string) {})
+
+			Convey("Then the chapter file should be kept as is", func() {
+				So(err, ShouldBeNil)
+				So(downloaded, ShouldEqual, path)
+
+				contents, err := filesystem.Api().ReadFile(path)
+				So(err, ShouldBeNil)
+				So(string(contents), ShouldEqual, "chapter")
+			})
+		})
+
+		Convey("Then it should be downloaded already", func() {
+			So(chapter.IsDownloaded(), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,101 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/log"
+	"github.com/preetbiswas12/Kage/provider"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// downloadFallback downloads the pages of the chapter from the same chapter of the fallback sources.
+// Sources are tried in the configured order, the source of the chapter itself is skipped
+func downloadFallback(ctx context.Context, chapter *source.Chapter, progress func(string)) error {
+	if chapter.Number == "" {
+		return errors.New("chapter number is unknown, it can not be found in other sources")
+	}
+
+	for _, name := range viper.GetStringSlice(key.DownloaderFallbackSources) {
+		if strings.EqualFold(name, chapter.Source().Name()) {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		p, ok := provider.Get(name)
+		if !ok {
+			log.Warnf("fallback source %s not found", name)
+			continue
+		}
+
+		progress(fmt.Sprintf("Trying %s", name))
+		fallback, err := fallbackChapter(ctx, p, chapter)
+		if err != nil {
+			log.Warnf("fallback source %s: %s", name, err)
+			continue
+		}
+
+		if _, err = source.PagesOf(ctx, fallback.Source(), fallback); err != nil {
+			log.Warnf("fallback source %s: %s", name, err)
+			continue
+		}
+
+		if err = fallback.DownloadPagesContext(ctx, false, progress); err != nil {
+			log.Warnf("fallback source %s: %s", name, err)
+			continue
+		}
+
+		log.Infof("downloaded %s from fallback source %s", chapter.Name, name)
+		chapter.UseFallback(fallback)
+		return nil
+	}
+
+	return fmt.Errorf("chapter %s was not found in any of the fallback sources", chapter.Number)
+}
+
+// fallbackChapter finds the chapter with the same number in the same manga of the source.
+// Chapters in the same language are preferred
+func fallbackChapter(ctx context.Context, p *provider.Provider, chapter *source.Chapter) (*source.Chapter, error) {
+	src, err := p.CreateSource()
+	if err != nil {
+		return nil, err
+	}
+
+	mangas, err := source.Search(ctx, src, chapter.Manga.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	manga, ok := source.FindSimilar(chapter.Manga.Name, mangas)
+	if !ok {
+		return nil, fmt.Errorf("manga %s not found", chapter.Manga.Name)
+	}
+
+	chapters, err := source.ChaptersOf(ctx, src, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := lo.Filter(chapters, func(c *source.Chapter, _ int) bool {
+		return c.Number == chapter.Number
+	})
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("chapter %s not found in %s", chapter.Number, manga.Name)
+	}
+
+	if c, ok := lo.Find(candidates, func(c *source.Chapter) bool {
+		return c.Language == chapter.Language
+	}); ok {
+		return c, nil
+	}
+
+	return candidates[0], nil
+}
@@ -0,0 +1,152 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/history"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/provider"
+	"github.com/preetbiswas12/Kage/source"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"strings"
+	"testing"
+)
+
+// brokenSource finds the chapters, but fails to load their pages
+type brokenSource struct {
+	searched int
+}
+
+func (*brokenSource) Name() string { return "Broken" }
+
+func (*brokenSource) ID() string { return "broken" }
+
+func (s *brokenSource) Search(string) ([]*source.Manga, error) {
+	s.searched++
+	return nil, nil
+}
+
+func (*brokenSource) ChaptersOf(*source.Manga) ([]*source.Chapter, error) { return nil, nil }
+
+func (*brokenSource) PagesOf(*source.Chapter) ([]*source.Page, error) {
+	return nil, errors.New("server is down")
+}
+
+// backupSource has the same chapters in several languages
+type backupSource struct{}
+
+func (backupSource) Name() string { return "Backup" }
+
+func (backupSource) ID() string { return "backup" }
+
+func (s backupSource) Search(string) ([]*source.Manga, error) {
+	return []*source.Manga{
+		{Name: "One Piece Party", URL: "/manga/party", Source: s},
+		{Name: "One Piece", URL: "/manga/one-piece", Source: s},
+	}, nil
+}
+
+func (backupSource) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
+	if manga.URL != "/manga/one-piece" {
+		return nil, errors.New("wrong manga")
+	}
+
+	manga.Chapters = []*source.Chapter{
+		{Name: "Romance Dawn (fr)", URL: "/chapter/1/fr", Number: "1", Language: "fr", Index: 1, Manga: manga},
+		{Name: "Romance Dawn", URL: "/chapter/1/en", Number: "1", Language: "en", Index: 2, Manga: manga},
+	}
+
+	return manga.Chapters, nil
+}
+
+func (backupSource) PagesOf(chapter *source.Chapter) ([]*source.Page, error) {
+	chapter.Pages = []*source.Page{{URL: chapter.URL + "/1.jpg", Index: 1, Extension: ".jpg", Chapter: chapter}}
+	return chapter.Pages, nil
+}
+
+func (backupSource) DownloadPage(_ context.Context, page *source.Page) error {
+	page.Contents = bytes.NewBufferString("image")
+	page.Size = uint64(page.Contents.Len())
+	return nil
+}
+
+func TestDownload_Fallback(t *testing.T) {
+	broken := &brokenSource{}
+	provider.Register(&provider.Provider{
+		ID:           broken.ID(),
+		Name:         broken.Name(),
+		CreateSource: func() (source.Source, error) { return broken, nil },
+	})
+	provider.Register(&provider.Provider{
+		ID:           backupSource{}.ID(),
+		Name:         backupSource{}.Name(),
+		CreateSource: func() (source.Source, error) { return backupSource{}, nil },
+	})
+
+	Convey("Given a chapter which pages fail to load", t, func() {
+		filesystem.SetMemMapFs()
+		broken.searched = 0
+
+		for name, value := range map[string]any{
+			key.DownloaderFallbackSources:    []string{"Broken", "Backup"},
+			key.FormatsUse:                   "plain",
+			key.HistorySaveOnDownload:        false,
+			key.DownloaderDownloadCover:      false,
+			key.MetadataFetchAnilist:         false,
+			key.MetadataSeriesJSON:           false,
+			key.DownloaderRedownloadExisting: false,
+		} {
+			previous := viper.Get(name)
+			viper.Set(name, value)
+			defer viper.Set(name, previous)
+		}
+
+		manga := &source.Manga{Name: "One Piece", URL: "/broken/one-piece", Source: broken}
+		chapter := &source.Chapter{Name: "Romance Dawn", URL: "/broken/chapter/1", Number: "1", Language: "en", Index: 1, Manga: manga}
+		manga.Chapters = []*source.Chapter{chapter}
+
+		// every run downloads the chapter anew
+		path, err := chapter.Path(false)
+		So(err, ShouldBeNil)
+		So(filesystem.Api().RemoveAll(path), ShouldBeNil)
+
+		Convey("When it is downloaded with the fallback sources", func() {
+			_, err := Download(chapter, func(string) {})
+
+			Convey("Then the pages should be taken from the same chapter of the fallback source", func() {
+				So(err, ShouldBeNil)
+				So(broken.searched, ShouldEqual, 0)
+				So(chapter.Fallback, ShouldNotBeNil)
+				So(chapter.Fallback.Source().Name(), ShouldEqual, "Backup")
+				So(chapter.Fallback.Manga.URL, ShouldEqual, "/manga/one-piece")
+				So(chapter.Fallback.Language, ShouldEqual, "en")
+				So(chapter.Pages, ShouldHaveLength, 1)
+			})
+
+			Convey("Then the fallback should be noted in ComicInfo", func() {
+				So(chapter.ComicInfo().Notes, ShouldContainSubstring, "Pages from Backup: /chapter/1/en.")
+			})
+
+			Convey("Then the fallback source should be saved in history", func() {
+				So(history.Save(chapter), ShouldBeNil)
+
+				saved, err := history.Get()
+				So(err, ShouldBeNil)
+
+				var found *history.SavedChapter
+				for _, c := range saved {
+					if strings.EqualFold(c.MangaName, manga.Name) {
+						found = c
+					}
+				}
+
+				So(found, ShouldNotBeNil)
+				So(found.SourceID, ShouldEqual, "broken")
+				So(found.FallbackSourceID, ShouldEqual, "backup")
+			})
+		})
+	})
+}
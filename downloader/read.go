@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"context"
 	"fmt"
 	"github.com/preetbiswas12/Kage/color"
 	"github.com/preetbiswas12/Kage/constant"
@@ -17,6 +18,11 @@ import (
 // Read the chapter by downloading it with the given source
 // and opening it with the configured reader.
 func Read(chapter *source.Chapter, progress func(string)) error {
+	return ReadContext(context.Background(), chapter, progress)
+}
+
+// ReadContext is the same as Read, but downloading is stopped once the context is done.
+func ReadContext(ctx context.Context, chapter *source.Chapter, progress func(string)) error {
 	if viper.GetBool(key.ReaderReadInBrowser) {
 		return open.StartWith(
 			chapter.URL,
@@ -34,13 +40,13 @@ func Read(chapter *source.Chapter, progress func(string)) error {
 	log.Infof("downloading %s for reading. Provider is %s", chapter.Name, chapter.Source().ID())
 	log.Infof("getting pages of %s", chapter.Name)
 	progress("Getting pages")
-	pages, err := chapter.Source().PagesOf(chapter)
+	pages, err := source.PagesOf(ctx, chapter.Source(), chapter)
 	if err != nil {
 		log.Error(err)
 		return err
 	}
 
-	err = chapter.DownloadPages(true, progress)
+	err = chapter.DownloadPagesContext(ctx, true, progress)
 	if err != nil {
 		log.Error(err)
 		return err
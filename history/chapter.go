@@ -14,7 +14,9 @@ type SavedChapter struct {
 	URL                string `json:"url"`
 	ID                 string `json:"id"`
 	Index              int    `json:"index"`
+	Number             string `json:"number,omitempty"`
 	MangaID            string `json:"manga_id"`
+	FallbackSourceID   string `json:"fallback_source_id,omitempty"`
 }
 
 func (c *SavedChapter) encode() string {
@@ -26,6 +28,11 @@ func (c *SavedChapter) String() string {
 }
 
 func newSavedChapter(chapter *source.Chapter) *SavedChapter {
+	var fallbackSourceID string
+	if chapter.Fallback != nil {
+		fallbackSourceID = chapter.Fallback.Source().ID()
+	}
+
 	return &SavedChapter{
 		SourceID:           chapter.Manga.Source.ID(),
 		MangaName:          chapter.Manga.Name,
@@ -36,5 +43,27 @@ func newSavedChapter(chapter *source.Chapter) *SavedChapter {
 		MangaID:            chapter.Manga.ID,
 		MangaChaptersTotal: len(chapter.Manga.Chapters),
 		Index:              int(chapter.Index),
+		Number:             chapter.Number,
+		FallbackSourceID:   fallbackSourceID,
+	}
+}
+
+// Find returns the position of the saved chapter in the chapters.
+// Chapters are matched by ID or URL, then by number, since positions change when the list is deduplicated
+func (c *SavedChapter) Find(chapters []*source.Chapter) (int, bool) {
+	for i, chapter := range chapters {
+		if (c.ID != "" && chapter.ID == c.ID) || (c.URL != "" && chapter.URL == c.URL) {
+			return i, true
+		}
 	}
-}
\ No newline at end of file
+
+	if c.Number != "" {
+		for i, chapter := range chapters {
+			if chapter.Number == c.Number {
+				return i, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -68,3 +68,43 @@ func TestHistory(t *testing.T) {
 		})
 	})
 }
+
+func TestSavedChapter_Find(t *testing.T) {
+	Convey("Given a saved chapter", t, func() {
+		saved := &SavedChapter{ID: "c3", URL: "https://example.com/3", Number: "3", Index: 5}
+
+		Convey("When the chapters were deduplicated", func() {
+			chapters := []*source.Chapter{
+				{ID: "c1", URL: "https://example.com/1", Number: "1"},
+				{ID: "c3", URL: "https://example.com/3", Number: "3"},
+			}
+
+			Convey("Then it should be found by its ID", func() {
+				i, ok := saved.Find(chapters)
+				So(ok, ShouldBeTrue)
+				So(i, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the chapter was replaced by another one with the same number", func() {
+			chapters := []*source.Chapter{
+				{ID: "c1", URL: "https://example.com/1", Number: "1"},
+				{ID: "other", URL: "https://other.com/3", Number: "3"},
+			}
+
+			Convey("Then it should be found by its number", func() {
+				i, ok := saved.Find(chapters)
+				So(ok, ShouldBeTrue)
+				So(i, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the chapter is missing", func() {
+			_, ok := saved.Find([]*source.Chapter{{ID: "c1", Number: "1"}})
+
+			Convey("Then it should not be found", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+	})
+}
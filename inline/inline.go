@@ -1,33 +1,38 @@
 package inline
 
 import (
+	"context"
+	"fmt"
 	"github.com/preetbiswas12/Kage/downloader"
 	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/log"
 	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
 	"github.com/spf13/viper"
 	"os"
 )
 
-func Run(options *Options) (err error) {
+// Run inline mode with the given options.
+// Searching and downloading are stopped once the context is done.
+func Run(ctx context.Context, options *Options) (err error) {
 	if options.Out == nil {
 		options.Out = os.Stdout
 	}
 
-	var mangas []*source.Manga
-	for _, src := range options.Sources {
-		m, err := src.Search(options.Query)
-		if err != nil {
-			return err
-		}
+	mangas, err := search(ctx, options)
+	if err != nil {
+		return err
+	}
 
-		mangas = append(mangas, m...)
+	// browse lists have no query to rank the merged mangas by
+	if options.Aggregate && len(options.Sources) > 1 && options.Browse == "" {
+		mangas = aggregate(mangas, options)
 	}
 
 	if options.MangaPicker.IsAbsent() && options.ChaptersFilter.IsAbsent() {
 		if viper.GetBool(key.MetadataFetchAnilist) {
 			for _, manga := range mangas {
-				_ = manga.PopulateMetadata(func(string) {})
+				_ = manga.PopulateMetadataContext(ctx, func(string) {})
 			}
 		}
 
@@ -44,7 +49,7 @@ func Run(options *Options) (err error) {
 	if options.MangaPicker.IsAbsent() {
 		// preload all chapters
 		for _, manga := range mangas {
-			if err = prepareManga(manga, options); err != nil {
+			if err = prepareManga(ctx, manga, options); err != nil {
 				return err
 			}
 		}
@@ -90,7 +95,12 @@ func Run(options *Options) (err error) {
 		return nil
 	}
 
-	chapters, err = manga.Source.ChaptersOf(manga)
+	chapters, err = source.ChaptersOf(ctx, manga.Source, manga)
+	if err != nil {
+		return err
+	}
+
+	chapters, err = source.DedupChapters(manga, chapters)
 	if err != nil {
 		return err
 	}
@@ -103,7 +113,7 @@ func Run(options *Options) (err error) {
 	}
 
 	if options.Json {
-		if err = prepareManga(manga, options); err != nil {
+		if err = prepareManga(ctx, manga, options); err != nil {
 			return err
 		}
 
@@ -118,9 +128,9 @@ func Run(options *Options) (err error) {
 
 	for _, chapter := range chapters {
 		if options.Download {
-			path, err := downloader.Download(chapter, func(string) {})
+			path, err := downloader.DownloadContext(ctx, chapter, func(string) {})
 			if err != nil {
-				if viper.GetBool(key.DownloaderStopOnError) {
+				if viper.GetBool(key.DownloaderStopOnError) || ctx.Err() != nil {
 					return err
 				}
 
@@ -132,7 +142,7 @@ func Run(options *Options) (err error) {
 				log.Warn(err)
 			}
 		} else {
-			err := downloader.Read(chapter, func(string) {})
+			err := downloader.ReadContext(ctx, chapter, func(string) {})
 			if err != nil {
 				return err
 			}
@@ -141,3 +151,97 @@ func Run(options *Options) (err error) {
 
 	return nil
 }
+
+// search loads the pages of results from all the sources concurrently.
+// A failed source is skipped, unless all of them have failed
+func search(ctx context.Context, options *Options) ([]*source.Manga, error) {
+	iterators := make([]*source.SearchIterator, len(options.Sources))
+	for i, src := range options.Sources {
+		it, err := searchIterator(src, options)
+		if err != nil {
+			return nil, err
+		}
+
+		iterators[i] = it
+	}
+
+	var mangas []*source.Manga
+	for page := 0; options.SearchPages <= 0 || page < options.SearchPages; page++ {
+		iterators = lo.Filter(iterators, func(it *source.SearchIterator, _ int) bool {
+			return it.HasNext()
+		})
+
+		if len(iterators) == 0 {
+			break
+		}
+
+		found, errs := source.SearchAll(ctx, iterators)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if len(errs) == len(iterators) {
+			return nil, errs[0]
+		}
+
+		for _, err := range errs {
+			log.Warn(err)
+		}
+
+		// failed sources are not asked for more pages
+		iterators = lo.Filter(iterators, func(it *source.SearchIterator, _ int) bool {
+			return !it.Failed()
+		})
+
+		mangas = append(mangas, found...)
+	}
+
+	return mangas, nil
+}
+
+// aggregate merges the same mangas from different sources.
+// The variant from the first source given is picked for each merged manga,
+// the others are kept as alternatives
+func aggregate(mangas []*source.Manga, options *Options) []*source.Manga {
+	options.alternatives = make(map[*source.Manga][]*source.Manga)
+
+	return lo.Map(source.Aggregate(options.Query, options.Sources, mangas), func(merged *source.AggregatedManga, _ int) *source.Manga {
+		picked := lo.MinBy(merged.Mangas, func(a, b *source.Manga) bool {
+			return lo.IndexOf(options.Sources, a.Source) < lo.IndexOf(options.Sources, b.Source)
+		})
+
+		options.alternatives[picked] = lo.Filter(merged.Mangas, func(manga *source.Manga, _ int) bool {
+			return manga != picked
+		})
+
+		return picked
+	})
+}
+
+// searchIterator creates an iterator over mangas of the source.
+// It browses the list given in the options or searches with the query and filters
+func searchIterator(src source.Source, options *Options) (*source.SearchIterator, error) {
+	if options.Browse != "" {
+		return source.NewBrowseIterator(src, options.Browse)
+	}
+
+	if len(options.Filters) == 0 {
+		return source.NewSearchIterator(src, options.Query), nil
+	}
+
+	definitions, err := source.FiltersOf(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(definitions) == 0 {
+		return nil, fmt.Errorf("source %s does not support filters", src.Name())
+	}
+
+	filters, err := options.Filters.Resolve(definitions)
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", src.Name(), err)
+	}
+
+	return source.NewFilteredSearchIterator(src, options.Query, filters), nil
+}
@@ -1,6 +1,7 @@
 package inline
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/preetbiswas12/Kage/anilist"
 	"github.com/preetbiswas12/Kage/key"
@@ -15,6 +16,8 @@ type Manga struct {
 	Mangal *source.Manga `json:"mangal" jsonschema:"description=Mangal variant of the manga"`
 	// Anilist is the closest anilist match to mangal manga
 	Anilist *anilist.Manga `json:"anilist" jsonschema:"description=Anilist is the closest anilist match to mangal manga"`
+	// Alternatives are the same manga found in other sources
+	Alternatives []*Manga `json:"alternatives,omitempty" jsonschema:"description=Alternatives are the same manga found in other sources."`
 }
 
 type Output struct {
@@ -35,6 +38,13 @@ func asJson(manga []*source.Manga, options *Options) (marshalled []byte, err err
 			Anilist: al,
 			Source:  manga.Source.Name(),
 		}
+
+		for _, alternative := range options.alternatives[manga] {
+			m[i].Alternatives = append(m[i].Alternatives, &Manga{
+				Mangal: alternative,
+				Source: alternative.Source.Name(),
+			})
+		}
 	}
 
 	return json.Marshal(&Output{
@@ -43,7 +53,7 @@ func asJson(manga []*source.Manga, options *Options) (marshalled []byte, err err
 	})
 }
 
-func prepareManga(manga *source.Manga, options *Options) error {
+func prepareManga(ctx context.Context, manga *source.Manga, options *Options) error {
 	var err error
 
 	if options.IncludeAnilistManga {
@@ -54,7 +64,12 @@ func prepareManga(manga *source.Manga, options *Options) error {
 	}
 
 	if options.ChaptersFilter.IsPresent() {
-		chapters, err := manga.Source.ChaptersOf(manga)
+		chapters, err := source.ChaptersOf(ctx, manga.Source, manga)
+		if err != nil {
+			return err
+		}
+
+		chapters, err = source.DedupChapters(manga, chapters)
 		if err != nil {
 			return err
 		}
@@ -68,7 +83,7 @@ func prepareManga(manga *source.Manga, options *Options) error {
 
 		if options.PopulatePages {
 			for _, chapter := range chapters {
-				_, err := chapter.Source().PagesOf(chapter)
+				_, err := source.PagesOf(ctx, chapter.Source(), chapter)
 				if err != nil {
 					return err
 				}
@@ -80,7 +95,7 @@ func prepareManga(manga *source.Manga, options *Options) error {
 	}
 
 	if viper.GetBool(key.MetadataFetchAnilist) {
-		_ = manga.PopulateMetadata(func(string) {})
+		_ = manga.PopulateMetadataContext(ctx, func(string) {})
 	}
 
 	return nil
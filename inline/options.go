@@ -25,8 +25,15 @@ type Options struct {
 	Json                bool
 	PopulatePages       bool
 	Query               string
+	SearchPages         int
+	Filters             source.Filters
+	Browse              source.BrowseList
+	Aggregate           bool
 	MangaPicker         mo.Option[MangaPicker]
 	ChaptersFilter      mo.Option[ChaptersFilter]
+
+	// alternatives are variants of the found mangas in other sources
+	alternatives map[*source.Manga][]*source.Manga
 }
 
 func ParseMangaPicker(query, description string) (MangaPicker, error) {
@@ -125,3 +132,22 @@ func ParseChaptersFilter(description string) (ChaptersFilter, error) {
 		}
 	}, nil
 }
+
+// ParseFilters parses search filters given as key=value pairs.
+// The same key may be given multiple times.
+func ParseFilters(descriptions []string) (source.Filters, error) {
+	filters := make(source.Filters)
+
+	for _, description := range descriptions {
+		k, v, ok := strings.Cut(description, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid filter pattern: %s, expected key=value", description)
+		}
+
+		filters.Add(k, v)
+	}
+
+	return filters, nil
+}
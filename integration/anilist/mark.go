@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/preetbiswas12/Kage/anilist"
 	"github.com/preetbiswas12/Kage/log"
+	"github.com/preetbiswas12/Kage/network"
 	"github.com/preetbiswas12/Kage/source"
 	"net/http"
 	"strconv"
@@ -34,12 +35,18 @@ func (a *Anilist) MarkRead(chapter *source.Chapter) error {
 		return err
 	}
 
+	// anilist progress is an integer, so chapter 12.5 counts as 12
+	progress := int(chapter.Index)
+	if number, err := strconv.ParseFloat(chapter.Number, 64); err == nil {
+		progress = int(number)
+	}
+
 	// prepare body
 	body := map[string]interface{}{
 		"query": markReadQuery,
 		"variables": map[string]interface{}{
 			"ID":       manga.ID,
-			"progress": chapter.Index,
+			"progress": progress,
 		},
 	}
 
@@ -69,7 +76,7 @@ func (a *Anilist) MarkRead(chapter *source.Chapter) error {
 
 	// send request
 	log.Info("Sending request to Anilist: " + string(jsonBody))
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := network.Client.Do(req)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -3,7 +3,7 @@ package key
 // DefinedFieldsCount is the number of fields defined in this package.
 // You have to manually update this number when you add a new field
 // to check later if every field has a defined default value
-const DefinedFieldsCount = 54
+const DefinedFieldsCount = 80
 
 const (
 	DownloaderPath                = "downloader.path"
@@ -17,6 +17,13 @@ const (
 	DownloaderRedownloadExisting  = "downloader.redownload_existing"
 	DownloaderReadDownloaded      = "downloader.read_downloaded"
 	DownloaderMaxConcurrentPages  = "downloader.max_concurrent_pages"
+	DownloaderFallbackSources     = "downloader.fallback_sources"
+)
+
+const (
+	ChaptersDedupPolicy      = "chapters.dedup_policy"
+	ChaptersPreferredGroups  = "chapters.preferred_groups"
+	ChaptersMangaDedupPolicy = "chapters.manga_dedup_policy"
 )
 
 const (
@@ -50,6 +57,7 @@ const (
 
 const (
 	SearchShowQuerySuggestions = "search.show_query_suggestions"
+	SearchAggregate            = "search.aggregate"
 )
 
 const (
@@ -62,8 +70,38 @@ const (
 
 const (
 	MangadexLanguage                = "mangadex.language"
+	MangadexMangaLanguage           = "mangadex.manga_language"
 	MangadexNSFW                    = "mangadex.nsfw"
 	MangadexShowUnavailableChapters = "mangadex.show_unavailable_chapters"
+	MangadexQuality                 = "mangadex.quality"
+)
+
+const (
+	OPDSURL      = "opds.url"
+	OPDSUsername = "opds.username"
+	OPDSPassword = "opds.password"
+)
+
+const (
+	NetworkSearchTimeout   = "network.search_timeout"
+	NetworkChaptersTimeout = "network.chapters_timeout"
+	NetworkPagesTimeout    = "network.pages_timeout"
+	NetworkPageTimeout     = "network.page_timeout"
+	NetworkRateLimit       = "network.rate_limit"
+	NetworkRateLimits      = "network.rate_limits"
+	NetworkProxy           = "network.proxy"
+	NetworkSourceProxies   = "network.source_proxies"
+	NetworkUserAgent       = "network.user_agent"
+	NetworkHeaders         = "network.headers"
+)
+
+const (
+	CacheSearchLifetime   = "cache.search_lifetime"
+	CacheChaptersLifetime = "cache.chapters_lifetime"
+	CachePagesLifetime    = "cache.pages_lifetime"
+	CacheRefresh          = "cache.refresh"
+	CacheHTTP             = "cache.http"
+	CacheHTTPStaleIfError = "cache.http_stale_if_error"
 )
 
 const (
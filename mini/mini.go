@@ -1,6 +1,7 @@
 package mini
 
 import (
+	"context"
 	"errors"
 	"github.com/preetbiswas12/Kage/source"
 	"github.com/preetbiswas12/Kage/util"
@@ -18,6 +19,9 @@ type Options struct {
 }
 
 type mini struct {
+	// ctx is used to cancel searching and downloading
+	ctx context.Context
+
 	width, height int
 
 	state         state
@@ -69,12 +73,13 @@ func (m *mini) newState(s state) {
 	m.setState(s)
 }
 
-func Run(options *Options) error {
+func Run(ctx context.Context, options *Options) error {
 	if options.Continue && options.Download {
 		return errors.New("cannot download and continue")
 	}
 
 	m := newMini()
+	m.ctx = ctx
 	m.state = sourceSelectState
 	if options.Continue {
 		m.state = historySelectState
@@ -90,7 +90,7 @@ func (m *mini) handleMangaSearchState() error {
 		query := in.value
 
 		erase := progress("Searching Query..")
-		m.cachedMangas[query], err = m.selectedSource.Search(query)
+		m.cachedMangas[query], err = source.Search(m.ctx, m.selectedSource, query)
 		max := lo.Min([]int{len(m.cachedMangas[query]), viper.GetInt(key.MiniSearchLimit)})
 		m.cachedMangas[query] = m.cachedMangas[query][:max]
 		erase()
@@ -127,16 +127,19 @@ func (m *mini) handleMangaSelectState() error {
 }
 
 func (m *mini) handleChapterSelectState() error {
-	var err error
-
 	erase := progress("Searching Chapters..")
-	m.cachedChapters[m.selectedManga.URL], err = m.selectedSource.ChaptersOf(m.selectedManga)
+	chapters, err := source.ChaptersOf(m.ctx, m.selectedSource, m.selectedManga)
 	erase()
 	if err != nil {
 		return err
 	}
 
-	chapters := m.cachedChapters[m.selectedManga.URL]
+	chapters, err = source.DedupChapters(m.selectedManga, chapters)
+	if err != nil {
+		return err
+	}
+
+	m.cachedChapters[m.selectedManga.URL] = chapters
 
 	if len(chapters) == 0 {
 		fail("No chapters found")
@@ -228,7 +231,7 @@ func (m *mini) handleChapterReadState() error {
 		util.ClearScreen()
 		var erase = func() {}
 
-		err = downloader.Read(chapter, func(s string) {
+		err = downloader.ReadContext(m.ctx, chapter, func(s string) {
 			erase()
 			erase = progress(s)
 		})
@@ -317,14 +320,14 @@ func (m *mini) handleChaptersDownloadState() error {
 
 		title(fmt.Sprintf("Currently downloading %s %s (%s)", chapter.Manga.Name, chapter.Name, m.selectedSource.Name()))
 
-		_, err := downloader.Download(chapter, func(s string) {
+		_, err := downloader.DownloadContext(m.ctx, chapter, func(s string) {
 			erase()
 			erase = progress(s)
 		})
 
 		erase()
 
-		if err != nil && viper.GetBool(key.DownloaderStopOnError) {
+		if err != nil && (viper.GetBool(key.DownloaderStopOnError) || m.ctx.Err() != nil) {
 			return err
 		}
 
@@ -404,15 +407,25 @@ func (m *mini) handleHistorySelectState() error {
 		ID:     c.MangaID,
 		Source: s,
 	}
-	chaps, err := m.selectedSource.ChaptersOf(manga)
+	chaps, err := source.ChaptersOf(m.ctx, m.selectedSource, manga)
 	erase()
 
 	if err != nil {
 		return err
 	}
 
+	chaps, err = source.DedupChapters(manga, chaps)
+	if err != nil {
+		return err
+	}
+
 	m.cachedChapters[manga.URL] = chaps
-	m.selectedChapters = chaps[c.Index-1:]
+	i, ok := c.Find(chaps)
+	if !ok {
+		return fmt.Errorf("chapter \"%s\" of %s was not found", c.Name, c.MangaName)
+	}
+
+	m.selectedChapters = chaps[i:]
 
 	m.newState(chapterReadState)
 	return nil
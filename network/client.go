@@ -15,11 +15,21 @@ func init() {
 	// Increased from 30s to 2 minutes to handle slow servers during large downloads
 	transport.ResponseHeaderTimeout = 2 * time.Minute
 	transport.ExpectContinueTimeout = 30 * time.Second
+	transport.Proxy = proxy
+
+	// libraries that create their own clients, such as the Mangadex one, use the default transport
+	http.DefaultTransport = Transport
 }
 
+// Transport is the transport of all the HTTP requests.
+// It sets the configured headers of the hosts, serves the cached responses
+// and waits for the rate limits of the hosts before sending requests through the configured proxies
+var Transport http.RoundTripper = &headerTransport{next: newHTTPCacheTransport(newRateLimitTransport(transport))}
+
 // Client is the default HTTP client used for downloads.
 // Timeout is set to 10 minutes to handle large file downloads in long-running operations.
 var Client = &http.Client{
 	Timeout:   10 * time.Minute,
-	Transport: transport,
+	Transport: Transport,
+	Jar:       Jar,
 }
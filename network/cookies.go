@@ -0,0 +1,343 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/where"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cookie is a cookie stored in the jar
+type Cookie struct {
+	// Domain of the cookie, without the leading dot
+	Domain string `json:"domain"`
+	// HostOnly is true if the cookie is not sent to the subdomains
+	HostOnly bool   `json:"host_only"`
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Secure   bool   `json:"secure"`
+	HttpOnly bool   `json:"http_only"`
+	// Expires is zero for the session cookies, they are kept until cleared
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+func (c *Cookie) id() string {
+	return c.Domain + ";" + c.Path + ";" + c.Name
+}
+
+func (c *Cookie) expired() bool {
+	return !c.Expires.IsZero() && c.Expires.Before(time.Now())
+}
+
+// url is the address the cookie is set for
+func (c *Cookie) url() *url.URL {
+	scheme := "http"
+	if c.Secure {
+		scheme = "https"
+	}
+
+	return &url.URL{Scheme: scheme, Host: c.Domain, Path: c.Path}
+}
+
+// same reports whether the cookies are the same, apart from expiration moved by less than a minute.
+// Servers that set the cookies with Max-Age renew them on every response
+func (c *Cookie) same(other *Cookie) bool {
+	expires := c.Expires.Sub(other.Expires)
+	return c.Value == other.Value &&
+		c.HostOnly == other.HostOnly &&
+		c.Secure == other.Secure &&
+		c.HttpOnly == other.HttpOnly &&
+		c.Expires.IsZero() == other.Expires.IsZero() &&
+		expires > -time.Minute && expires < time.Minute
+}
+
+func (c *Cookie) httpCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HttpOnly: c.HttpOnly,
+		Expires:  c.Expires,
+	}
+
+	if !c.HostOnly {
+		cookie.Domain = c.Domain
+	}
+
+	return cookie
+}
+
+// persistentJar is the cookie jar that is saved to the disk on every change.
+// Matching cookies to the requests is left to the standard jar
+type persistentJar struct {
+	once  sync.Once
+	mutex sync.Mutex
+
+	jar     *cookiejar.Jar
+	cookies map[string]*Cookie
+}
+
+// Jar is the cookie jar shared by all the HTTP clients
+var Jar = &persistentJar{}
+
+// load reads the saved cookies once
+func (j *persistentJar) load() {
+	j.once.Do(func() {
+		j.cookies = make(map[string]*Cookie)
+
+		var saved []*Cookie
+		if data, err := filesystem.Api().ReadFile(where.Cookies()); err == nil {
+			_ = json.Unmarshal(data, &saved)
+		}
+
+		for _, cookie := range saved {
+			j.cookies[cookie.id()] = cookie
+		}
+
+		j.reset()
+	})
+}
+
+// reset fills the standard jar with the cookies
+func (j *persistentJar) reset() {
+	j.jar, _ = cookiejar.New(nil)
+	for _, cookie := range j.cookies {
+		if !cookie.expired() {
+			j.jar.SetCookies(cookie.url(), []*http.Cookie{cookie.httpCookie()})
+		}
+	}
+}
+
+// save writes the cookies to the disk
+func (j *persistentJar) save() error {
+	cookies := make([]*Cookie, 0, len(j.cookies))
+	for _, cookie := range j.cookies {
+		if !cookie.expired() {
+			cookies = append(cookies, cookie)
+		}
+	}
+
+	data, err := json.MarshalIndent(cookies, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	// session and clearance cookies are credentials, only the user may read them
+	if err = filesystem.Api().WriteFile(where.Cookies(), data, 0600); err != nil {
+		return err
+	}
+
+	// files saved before were readable by everyone
+	return filesystem.Api().Chmod(where.Cookies(), 0600)
+}
+
+func (j *persistentJar) Cookies(u *url.URL) []*http.Cookie {
+	j.load()
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return j.jar.Cookies(u)
+}
+
+func (j *persistentJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.load()
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.jar.SetCookies(u, cookies)
+
+	var changed bool
+	host := strings.ToLower(u.Hostname())
+	for _, c := range cookies {
+		cookie := &Cookie{
+			Domain:   strings.TrimPrefix(strings.ToLower(c.Domain), "."),
+			Path:     c.Path,
+			Name:     c.Name,
+			Value:    c.Value,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+			Expires:  c.Expires,
+		}
+
+		if cookie.Domain == "" {
+			cookie.Domain = host
+			cookie.HostOnly = true
+		} else if host != cookie.Domain && !strings.HasSuffix(host, "."+cookie.Domain) {
+			// rejected by the standard jar as well
+			continue
+		}
+
+		if cookie.Path == "" || !strings.HasPrefix(cookie.Path, "/") {
+			cookie.Path = "/"
+		}
+
+		switch {
+		case c.MaxAge > 0:
+			cookie.Expires = time.Now().Add(time.Duration(c.MaxAge) * time.Second)
+		case c.MaxAge < 0:
+			cookie.Expires = time.Unix(1, 0)
+		}
+
+		stored, ok := j.cookies[cookie.id()]
+		switch {
+		case cookie.expired():
+			if ok {
+				delete(j.cookies, cookie.id())
+				changed = true
+			}
+		case !ok || !stored.same(cookie):
+			j.cookies[cookie.id()] = cookie
+			changed = true
+		}
+	}
+
+	// the file is rewritten only when needed, most responses set the same cookies again.
+	// Failing to save the cookies is not an error, they are still used in this session
+	if changed {
+		_ = j.save()
+	}
+}
+
+// Cookies returns the stored cookies that are not expired, sorted by domain.
+// Only the cookies of the given domain and its subdomains are returned if the domain is not empty
+func Cookies(domain string) []*Cookie {
+	Jar.load()
+
+	Jar.mutex.Lock()
+	defer Jar.mutex.Unlock()
+
+	var cookies []*Cookie
+	for _, cookie := range Jar.cookies {
+		if !cookie.expired() && matchesDomain(cookie.Domain, domain) {
+			cookies = append(cookies, cookie)
+		}
+	}
+
+	sort.Slice(cookies, func(i, j int) bool {
+		if cookies[i].Domain != cookies[j].Domain {
+			return cookies[i].Domain < cookies[j].Domain
+		}
+
+		return cookies[i].Name < cookies[j].Name
+	})
+
+	return cookies
+}
+
+// ClearCookies removes the stored cookies of the domain and its subdomains, all of them if the domain is empty.
+// Returns the number of the removed cookies
+func ClearCookies(domain string) (int, error) {
+	Jar.load()
+
+	Jar.mutex.Lock()
+	defer Jar.mutex.Unlock()
+
+	var removed int
+	for id, cookie := range Jar.cookies {
+		if matchesDomain(cookie.Domain, domain) {
+			delete(Jar.cookies, id)
+			removed++
+		}
+	}
+
+	Jar.reset()
+	return removed, Jar.save()
+}
+
+// ImportCookies adds the cookies in the Netscape format, as exported by the browsers, to the jar.
+// Returns the number of the imported cookies
+func ImportCookies(r io.Reader) (int, error) {
+	cookies, err := parseNetscapeCookies(r)
+	if err != nil {
+		return 0, err
+	}
+
+	Jar.load()
+
+	Jar.mutex.Lock()
+	defer Jar.mutex.Unlock()
+
+	var imported int
+	for _, cookie := range cookies {
+		if cookie.expired() {
+			continue
+		}
+
+		Jar.cookies[cookie.id()] = cookie
+		Jar.jar.SetCookies(cookie.url(), []*http.Cookie{cookie.httpCookie()})
+		imported++
+	}
+
+	return imported, Jar.save()
+}
+
+// parseNetscapeCookies parses the cookies.txt file.
+// Each line is domain, subdomains flag, path, secure flag, expiration, name and value separated by tabs
+func parseNetscapeCookies(r io.Reader) ([]*Cookie, error) {
+	const httpOnlyPrefix = "#HttpOnly_"
+
+	var cookies []*Cookie
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		var httpOnly bool
+		if strings.HasPrefix(text, httpOnlyPrefix) {
+			httpOnly = true
+			text = strings.TrimPrefix(text, httpOnlyPrefix)
+		}
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("line %d: expected 7 tab separated fields, got %d", line, len(fields))
+		}
+
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid expiration: %w", line, err)
+		}
+
+		cookie := &Cookie{
+			Domain:   strings.TrimPrefix(strings.ToLower(fields[0]), "."),
+			HostOnly: !strings.EqualFold(fields[1], "TRUE"),
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	return cookies, scanner.Err()
+}
+
+// matchesDomain reports whether the cookie domain is the domain or its subdomain.
+// Empty domain matches all
+func matchesDomain(cookieDomain, domain string) bool {
+	domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	return domain == "" || cookieDomain == domain || strings.HasSuffix(cookieDomain, "."+domain)
+}
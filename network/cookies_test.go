@@ -0,0 +1,124 @@
+package network
+
+import (
+	"fmt"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/where"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookies(t *testing.T) {
+	Convey("Given an empty cookie jar", t, func() {
+		filesystem.SetMemMapFs()
+		Jar = &persistentJar{}
+
+		future := time.Now().Add(time.Hour).Unix()
+		cookiesTxt := fmt.Sprintf(`# Netscape HTTP Cookie File
+.example.com	TRUE	/	TRUE	%d	cf_clearance	abc
+#HttpOnly_mangapill.com	FALSE	/	FALSE	0	session	xyz
+.example.com	TRUE	/	FALSE	1	expired	old
+`, future)
+
+		Convey("When importing cookies.txt", func() {
+			imported, err := ImportCookies(strings.NewReader(cookiesTxt))
+			So(err, ShouldBeNil)
+
+			Convey("Then the cookies that are not expired should be imported", func() {
+				So(imported, ShouldEqual, 2)
+
+				cookies := Cookies("")
+				So(cookies, ShouldHaveLength, 2)
+				So(cookies[0].Domain, ShouldEqual, "example.com")
+				So(cookies[1].HttpOnly, ShouldBeTrue)
+				So(cookies[1].HostOnly, ShouldBeTrue)
+			})
+
+			Convey("Then they should be sent to the matching hosts", func() {
+				u, _ := url.Parse("https://cdn.example.com/image.png")
+				So(Jar.Cookies(u), ShouldHaveLength, 1)
+				So(Jar.Cookies(u)[0].Value, ShouldEqual, "abc")
+
+				// secure cookie
+				u, _ = url.Parse("http://example.com")
+				So(Jar.Cookies(u), ShouldBeEmpty)
+
+				// host only cookie
+				u, _ = url.Parse("https://cdn.mangapill.com")
+				So(Jar.Cookies(u), ShouldBeEmpty)
+			})
+
+			Convey("Then they should be kept after a restart", func() {
+				Jar = &persistentJar{}
+				So(Cookies(""), ShouldHaveLength, 2)
+			})
+
+			Convey("And clearing the cookies of a domain", func() {
+				removed, err := ClearCookies("example.com")
+				So(err, ShouldBeNil)
+
+				Convey("Then only its cookies should be removed", func() {
+					So(removed, ShouldEqual, 1)
+					So(Cookies(""), ShouldHaveLength, 1)
+
+					u, _ := url.Parse("https://example.com")
+					So(Jar.Cookies(u), ShouldBeEmpty)
+				})
+			})
+		})
+
+		Convey("When a website sets cookies", func() {
+			u, _ := url.Parse("https://mangapill.com/manga/1")
+			Jar.SetCookies(u, []*http.Cookie{
+				{Name: "visited", Value: "1", MaxAge: 60},
+				{Name: "other", Value: "2", Domain: "example.com"},
+			})
+
+			Convey("Then the valid ones should be saved", func() {
+				cookies := Cookies("mangapill.com")
+				So(cookies, ShouldHaveLength, 1)
+				So(cookies[0].Path, ShouldEqual, "/")
+				So(cookies[0].Expires, ShouldHappenAfter, time.Now())
+				So(Cookies("example.com"), ShouldBeEmpty)
+			})
+
+			Convey("Then only the user should be able to read them", func() {
+				info, err := filesystem.Api().Stat(where.Cookies())
+				So(err, ShouldBeNil)
+				So(info.Mode().Perm(), ShouldEqual, os.FileMode(0600))
+			})
+
+			Convey("And setting the same cookies again", func() {
+				So(filesystem.Api().Remove(where.Cookies()), ShouldBeNil)
+				Jar.SetCookies(u, []*http.Cookie{{Name: "visited", Value: "1", MaxAge: 60}})
+
+				Convey("Then the file should not be rewritten", func() {
+					exists, err := filesystem.Api().Exists(where.Cookies())
+					So(err, ShouldBeNil)
+					So(exists, ShouldBeFalse)
+				})
+			})
+
+			Convey("And deleting one", func() {
+				Jar.SetCookies(u, []*http.Cookie{{Name: "visited", MaxAge: -1}})
+
+				Convey("Then it should be removed", func() {
+					So(Cookies(""), ShouldBeEmpty)
+				})
+			})
+		})
+
+		Convey("When the file is malformed", func() {
+			_, err := ImportCookies(strings.NewReader("example.com\tTRUE\t/"))
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
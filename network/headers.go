@@ -0,0 +1,100 @@
+package network
+
+import (
+	"github.com/preetbiswas12/Kage/constant"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/spf13/viper"
+	"net/http"
+	"strings"
+)
+
+// UserAgent returns the User-Agent sent with the requests unless overridden for the host
+func UserAgent() string {
+	if userAgent := strings.TrimSpace(viper.GetString(key.NetworkUserAgent)); userAgent != "" {
+		return userAgent
+	}
+
+	return constant.UserAgent
+}
+
+// parseHeaders parses the "domain=Header: value" entries of the config
+func parseHeaders(entries []string) map[string]http.Header {
+	headers := make(map[string]http.Header)
+	for _, entry := range entries {
+		domain, header, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+
+		name, value, ok := strings.Cut(header, ":")
+		if !ok {
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if _, ok := headers[domain]; !ok {
+			headers[domain] = make(http.Header)
+		}
+
+		headers[domain].Add(name, strings.TrimSpace(value))
+	}
+
+	return headers
+}
+
+// HostHeaders returns the headers configured for the host.
+// Domain headers apply to their subdomains as well, the most specific ones take precedence
+func HostHeaders(host string) http.Header {
+	headers := parseHeaders(viper.GetStringSlice(key.NetworkHeaders))
+
+	var domains []string
+	for domain := strings.ToLower(host); domain != ""; {
+		domains = append(domains, domain)
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+
+		domain = parent
+	}
+
+	hostHeaders := make(http.Header)
+	for i := len(domains) - 1; i >= 0; i-- {
+		for name, values := range headers[domains[i]] {
+			hostHeaders[name] = values
+		}
+	}
+
+	return hostHeaders
+}
+
+// headerTransport sets the configured headers of the hosts to the requests.
+// Configured headers replace the ones set by the sources, so that they can be fixed without code changes
+type headerTransport struct {
+	next http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers := HostHeaders(req.URL.Hostname())
+	if len(headers) == 0 && req.Header.Get("User-Agent") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	// transports must not modify the original request
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", UserAgent())
+	}
+
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,67 @@
+package network
+
+import (
+	"github.com/preetbiswas12/Kage/constant"
+	"github.com/preetbiswas12/Kage/key"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordTransport struct {
+	req *http.Request
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.req = req
+	return httptest.NewRecorder().Result(), nil
+}
+
+func TestHeaders(t *testing.T) {
+	Convey("Given the headers settings", t, func() {
+		viper.Set(key.NetworkUserAgent, "kage-test")
+		viper.Set(key.NetworkHeaders, []string{
+			"example.com=Referer: https://example.com/",
+			"example.com=Accept: text/html",
+			"cdn.example.com=Referer: https://www.example.com/reader",
+			"cdn.example.com=User-Agent: Mozilla/5.0",
+			"broken",
+		})
+		defer viper.Set(key.NetworkUserAgent, constant.UserAgent)
+		defer viper.Set(key.NetworkHeaders, nil)
+
+		Convey("Then the most specific domain headers should be used", func() {
+			headers := HostHeaders("img.cdn.example.com")
+			So(headers.Get("Referer"), ShouldEqual, "https://www.example.com/reader")
+			So(headers.Get("Accept"), ShouldEqual, "text/html")
+			So(headers.Get("User-Agent"), ShouldEqual, "Mozilla/5.0")
+
+			So(HostHeaders("example.org"), ShouldBeEmpty)
+		})
+
+		Convey("When sending a request", func() {
+			next := &recordTransport{}
+			transport := &headerTransport{next: next}
+
+			req, err := http.NewRequest(http.MethodGet, "https://example.com/manga", nil)
+			So(err, ShouldBeNil)
+			req.Header.Set("Referer", "https://google.com")
+
+			_, err = transport.RoundTrip(req)
+			So(err, ShouldBeNil)
+
+			Convey("Then the configured headers should replace the ones of the request", func() {
+				So(next.req.Header.Get("Referer"), ShouldEqual, "https://example.com/")
+				So(next.req.Header.Get("Accept"), ShouldEqual, "text/html")
+				So(next.req.Header.Get("User-Agent"), ShouldEqual, "kage-test")
+			})
+
+			Convey("Then the original request should not be modified", func() {
+				So(req.Header.Get("Referer"), ShouldEqual, "https://google.com")
+				So(req.Header.Get("Accept"), ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -0,0 +1,401 @@
+package network
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/where"
+	"github.com/spf13/viper"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxHTTPCacheEntry is the size of the largest response body to cache
+const maxHTTPCacheEntry = 16 << 20
+
+// FromCacheHeader is set to the responses served from the HTTP cache,
+// either fresh or revalidated
+const FromCacheHeader = "X-From-Cache"
+
+// cacheableTypes are the media types of the cached responses.
+// Listings are HTML, JSON or XML documents, page images are not worth revalidating
+var cacheableTypes = []string{"text/", "application/json", "application/xml", "+json", "+xml"}
+
+// httpCacheEntry is the stored response with the information needed to reuse it
+type httpCacheEntry struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// Vary are the values of the request headers the response varies by
+	Vary map[string]string `json:"vary,omitempty"`
+	// Stored is the time the response was received or revalidated
+	Stored time.Time `json:"stored"`
+}
+
+// cacheControl parses the Cache-Control header directives
+func cacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+			}
+		}
+	}
+
+	return directives
+}
+
+// seconds parses the delta-seconds value of the directive
+func seconds(value string) (time.Duration, bool) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+
+	return time.Duration(n) * time.Second, true
+}
+
+// date returns the Date header of the entry, the time it was stored if missing
+func (e *httpCacheEntry) date() time.Time {
+	if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		return date
+	}
+
+	return e.Stored
+}
+
+// age is the time since the response was generated by the server, RFC 7234 section 4.2.3
+func (e *httpCacheEntry) age() time.Duration {
+	age := time.Since(e.Stored)
+	if initial, ok := seconds(e.Header.Get("Age")); ok {
+		age += initial
+	}
+
+	return age
+}
+
+// lifetime is the time the response is fresh for, RFC 7234 section 4.2.1.
+// Responses with Last-Modified and without explicit expiration are fresh for 10% of the time since their modification
+func (e *httpCacheEntry) lifetime() time.Duration {
+	directives := cacheControl(e.Header)
+	if _, ok := directives["no-cache"]; ok {
+		return 0
+	}
+
+	if maxAge, ok := seconds(directives["max-age"]); ok {
+		return maxAge
+	}
+
+	if expires, err := http.ParseTime(e.Header.Get("Expires")); err == nil {
+		return expires.Sub(e.date())
+	} else if e.Header.Get("Expires") != "" {
+		// invalid dates stand for the past
+		return 0
+	}
+
+	if modified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil {
+		return e.date().Sub(modified) / 10
+	}
+
+	return 0
+}
+
+// fresh reports whether the entry can be used without revalidation
+func (e *httpCacheEntry) fresh() bool {
+	return e.age() < e.lifetime()
+}
+
+// matches reports whether the entry was stored for the request with the same varying headers
+func (e *httpCacheEntry) matches(req *http.Request) bool {
+	for name, value := range e.Vary {
+		if req.Header.Get(name) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// response creates the response to the request from the entry
+func (e *httpCacheEntry) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set(FromCacheHeader, "1")
+	header.Set("Age", strconv.Itoa(int(e.age().Seconds())))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// stale creates the response to the request from the stale entry, RFC 7234 section 4.2.4
+func (e *httpCacheEntry) stale(req *http.Request) *http.Response {
+	resp := e.response(req)
+	resp.Header.Add("Warning", `110 - "Response is Stale"`)
+	return resp
+}
+
+// cacheable reports whether the response to the request may be stored, RFC 7234 section 3
+func cacheable(req *http.Request, resp *http.Response) bool {
+	if req.Method != http.MethodGet || resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	if resp.ContentLength > maxHTTPCacheEntry {
+		return false
+	}
+
+	if _, ok := cacheControl(req.Header)["no-store"]; ok {
+		return false
+	}
+
+	directives := cacheControl(resp.Header)
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+
+	// responses for the authenticated users are not shared, but the cache is
+	if _, ok := directives["private"]; ok {
+		return false
+	}
+
+	if req.Header.Get("Authorization") != "" {
+		if _, ok := directives["public"]; !ok {
+			return false
+		}
+	}
+
+	if strings.TrimSpace(resp.Header.Get("Vary")) == "*" {
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+
+	for _, cacheableType := range cacheableTypes {
+		if strings.HasPrefix(mediaType, cacheableType) || strings.HasSuffix(mediaType, cacheableType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// httpCacheTransport caches the responses on disk and revalidates them with conditional requests
+// once they are stale, as described in RFC 7234. Stale responses are served if the server can't be reached
+type httpCacheTransport struct {
+	next http.RoundTripper
+}
+
+func newHTTPCacheTransport(next http.RoundTripper) *httpCacheTransport {
+	return &httpCacheTransport{next: next}
+}
+
+// httpCacheDir is the directory of the cached responses
+func httpCacheDir() string {
+	return filepath.Join(where.Cache(), "http")
+}
+
+// path of the cached response to the request
+func (t *httpCacheTransport) path(req *http.Request) string {
+	hash := sha1.Sum([]byte(req.URL.String()))
+	return filepath.Join(httpCacheDir(), hex.EncodeToString(hash[:]))
+}
+
+// load reads the cached response to the request, if any
+func (t *httpCacheTransport) load(req *http.Request) (*httpCacheEntry, bool) {
+	data, err := filesystem.Api().ReadFile(t.path(req))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry httpCacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil || entry.URL != req.URL.String() || !entry.matches(req) {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// store writes the entry to the disk, failing to do so is not an error
+func (t *httpCacheTransport) store(req *http.Request, entry *httpCacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if err = filesystem.Api().MkdirAll(httpCacheDir(), os.ModePerm); err == nil {
+		_ = filesystem.Api().WriteFile(t.path(req), data, os.ModePerm)
+	}
+}
+
+// remove deletes the cached response to the request
+func (t *httpCacheTransport) remove(req *http.Request) {
+	_ = filesystem.Api().Remove(t.path(req))
+}
+
+func (t *httpCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !viper.GetBool(key.CacheHTTP) || req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	// partial and conditional requests of the clients are theirs to handle
+	for _, name := range []string{"Range", "If-None-Match", "If-Modified-Since"} {
+		if req.Header.Get(name) != "" {
+			return t.next.RoundTrip(req)
+		}
+	}
+
+	entry, ok := t.load(req)
+	if !ok {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+
+		return t.save(req, resp)
+	}
+
+	_, noCache := cacheControl(req.Header)["no-cache"]
+	if entry.fresh() && !noCache && !viper.GetBool(key.CacheRefresh) {
+		return entry.response(req), nil
+	}
+
+	resp, err := t.next.RoundTrip(t.conditional(req, entry))
+	if err != nil {
+		// cancelled and timed out requests must fail, not succeed with the old data
+		if req.Context().Err() == nil && t.staleIfError(entry) {
+			return entry.stale(req), nil
+		}
+
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		_ = resp.Body.Close()
+
+		// headers of the 304 response replace the stored ones, RFC 7234 section 4.3.4
+		for name, values := range resp.Header {
+			if name != "Set-Cookie" {
+				entry.Header[name] = values
+			}
+		}
+
+		entry.Stored = time.Now()
+		t.store(req, entry)
+		return entry.response(req), nil
+	case resp.StatusCode >= http.StatusInternalServerError && t.staleIfError(entry):
+		_ = resp.Body.Close()
+		return entry.stale(req), nil
+	default:
+		return t.save(req, resp)
+	}
+}
+
+// conditional returns the request to revalidate the entry with its validators, RFC 7232
+func (t *httpCacheTransport) conditional(req *http.Request, entry *httpCacheEntry) *http.Request {
+	etag, modified := entry.Header.Get("ETag"), entry.Header.Get("Last-Modified")
+	if etag == "" && modified == "" {
+		return req
+	}
+
+	// transports must not modify the original request
+	req = req.Clone(req.Context())
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	if modified != "" {
+		req.Header.Set("If-Modified-Since", modified)
+	}
+
+	return req
+}
+
+// staleIfError reports whether the stale entry may be served when the server fails
+func (t *httpCacheTransport) staleIfError(entry *httpCacheEntry) bool {
+	if _, ok := cacheControl(entry.Header)["must-revalidate"]; ok {
+		return false
+	}
+
+	return viper.GetBool(key.CacheHTTPStaleIfError)
+}
+
+// save stores the response if it is cacheable and returns it with the body replaced by the read copy
+func (t *httpCacheTransport) save(req *http.Request, resp *http.Response) (*http.Response, error) {
+	if !cacheable(req, resp) {
+		if resp.StatusCode == http.StatusOK {
+			// the resource changed and is not cacheable anymore
+			t.remove(req)
+		}
+
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPCacheEntry+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+
+	if len(body) > maxHTTPCacheEntry {
+		// too large to be cached, pass the rest of the body through
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	entry := &httpCacheEntry{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		Stored:     time.Now(),
+	}
+
+	// cookies are kept by the jar, replaying them would overwrite the newer ones
+	entry.Header.Del("Set-Cookie")
+
+	for _, names := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(names, ",") {
+			if name = http.CanonicalHeaderKey(strings.TrimSpace(name)); name != "" {
+				if entry.Vary == nil {
+					entry.Vary = make(map[string]string)
+				}
+
+				entry.Vary[name] = req.Header.Get(name)
+			}
+		}
+	}
+
+	t.store(req, entry)
+	return resp, nil
+}
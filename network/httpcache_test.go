@@ -0,0 +1,139 @@
+package network
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPCache(t *testing.T) {
+	Convey("Given the HTTP cache", t, func() {
+		filesystem.SetMemMapFs()
+		viper.Set(key.CacheHTTP, true)
+		viper.Set(key.CacheHTTPStaleIfError, true)
+		defer viper.Set(key.CacheHTTP, false)
+		defer viper.Set(key.CacheHTTPStaleIfError, false)
+
+		var requests, conditional int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+
+			switch r.URL.Path {
+			case "/fresh":
+				w.Header().Set("Cache-Control", "max-age=60")
+			case "/etag":
+				w.Header().Set("ETag", `"v1"`)
+				w.Header().Set("Cache-Control", "no-cache")
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					conditional++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			case "/modified":
+				modified := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+				w.Header().Set("Last-Modified", modified)
+				w.Header().Set("Cache-Control", "max-age=0")
+				if r.Header.Get("If-Modified-Since") == modified {
+					conditional++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+			case "/image":
+				w.Header().Set("Cache-Control", "max-age=60")
+				w.Header().Set("Content-Type", "image/png")
+			case "/private":
+				w.Header().Set("Cache-Control", "private, max-age=60")
+			}
+
+			if w.Header().Get("Content-Type") == "" {
+				w.Header().Set("Content-Type", "application/json")
+			}
+
+			_, _ = w.Write([]byte(`{"chapters": []}`))
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: newHTTPCacheTransport(transport)}
+		get := func(path string) *http.Response {
+			resp, err := client.Get(server.URL + path)
+			So(err, ShouldBeNil)
+
+			body, err := io.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			So(resp.Body.Close(), ShouldBeNil)
+			So(string(body), ShouldEqual, `{"chapters": []}`)
+
+			return resp
+		}
+
+		Convey("Then fresh responses should be served from the cache", func() {
+			So(get("/fresh").Header.Get(FromCacheHeader), ShouldBeEmpty)
+			So(get("/fresh").Header.Get(FromCacheHeader), ShouldEqual, "1")
+			So(requests, ShouldEqual, 1)
+		})
+
+		Convey("Then stale responses should be revalidated with ETag", func() {
+			get("/etag")
+			resp := get("/etag")
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			So(resp.Header.Get(FromCacheHeader), ShouldEqual, "1")
+			So(conditional, ShouldEqual, 1)
+		})
+
+		Convey("Then stale responses should be revalidated with Last-Modified", func() {
+			get("/modified")
+			So(get("/modified").Header.Get(FromCacheHeader), ShouldEqual, "1")
+			So(conditional, ShouldEqual, 1)
+		})
+
+		Convey("Then refreshing should revalidate fresh responses", func() {
+			viper.Set(key.CacheRefresh, true)
+			defer viper.Set(key.CacheRefresh, false)
+
+			get("/fresh")
+			get("/fresh")
+			So(requests, ShouldEqual, 2)
+		})
+
+		Convey("Then images and private responses should not be cached", func() {
+			get("/image")
+			get("/image")
+			get("/private")
+			get("/private")
+			So(requests, ShouldEqual, 4)
+		})
+
+		Convey("When the server can't be reached", func() {
+			get("/etag")
+			server.Close()
+
+			Convey("Then the stale response should be served", func() {
+				resp := get("/etag")
+				So(resp.Header.Get("Warning"), ShouldContainSubstring, "110")
+			})
+
+			Convey("Then cancelled requests should fail", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/etag", nil)
+				So(err, ShouldBeNil)
+
+				_, err = client.Do(req)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Then requests without cached responses should fail", func() {
+				_, err := client.Get(server.URL + "/fresh")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package network
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/spf13/viper"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket that allows rate requests per second with bursts of burst requests
+type bucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64) *bucket {
+	burst := math.Max(1, math.Ceil(rate))
+	return &bucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+// Tokens may go negative, so that the waiting requests are queued
+func (b *bucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns the reserved token
+func (b *bucket) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// wait blocks until the token is available or the context is done
+func (b *bucket) wait(ctx context.Context) error {
+	delay := b.reserve()
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRateLimits parses the "domain=rate" entries of the config
+func parseRateLimits(entries []string) map[string]float64 {
+	limits := make(map[string]float64, len(entries))
+	for _, entry := range entries {
+		domain, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			continue
+		}
+
+		limits[strings.ToLower(strings.TrimSpace(domain))] = rate
+	}
+
+	return limits
+}
+
+// hostRateLimit returns requests per second allowed for the host.
+// Domain limits apply to their subdomains as well, the most specific one is used
+func hostRateLimit(host string) float64 {
+	limits := parseRateLimits(viper.GetStringSlice(key.NetworkRateLimits))
+
+	for domain := strings.ToLower(host); domain != ""; {
+		if rate, ok := limits[domain]; ok {
+			return rate
+		}
+
+		_, parent, ok := strings.Cut(domain, ".")
+		if !ok {
+			break
+		}
+
+		domain = parent
+	}
+
+	return viper.GetFloat64(key.NetworkRateLimit)
+}
+
+// rateLimitTransport limits requests to each host, so that the servers don't block us.
+// Buckets are shared by all the requests to the host, whoever makes them
+type rateLimitTransport struct {
+	next http.RoundTripper
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+func newRateLimitTransport(next http.RoundTripper) *rateLimitTransport {
+	return &rateLimitTransport{
+		next:    next,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// bucket returns the bucket of the host, nil if the host is not limited
+func (t *rateLimitTransport) bucket(host string) *bucket {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	b, ok := t.buckets[host]
+	if !ok {
+		if rate := hostRateLimit(host); rate > 0 {
+			b = newBucket(rate)
+		}
+
+		t.buckets[host] = b
+	}
+
+	return b
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if b := t.bucket(req.URL.Hostname()); b != nil {
+		if err := b.wait(req.Context()); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,55 @@
+package network
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/key"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	Convey("Given a bucket of 20 requests per second", t, func() {
+		b := newBucket(20)
+
+		Convey("When the burst is used up", func() {
+			for i := 0; i < 20; i++ {
+				So(b.reserve(), ShouldEqual, 0)
+			}
+
+			Convey("Then the next request should wait for a token", func() {
+				start := time.Now()
+				So(b.wait(context.Background()), ShouldBeNil)
+				So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 40*time.Millisecond)
+			})
+
+			Convey("Then the waiting should stop with the context", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				So(b.wait(ctx), ShouldEqual, context.Canceled)
+			})
+		})
+	})
+}
+
+func TestHostRateLimit(t *testing.T) {
+	Convey("Given the rate limits of the domains", t, func() {
+		viper.Set(key.NetworkRateLimit, 0)
+		viper.Set(key.NetworkRateLimits, []string{"example.com=2", "api.example.com=5", "free.example.com=0", "invalid"})
+		defer viper.Set(key.NetworkRateLimits, nil)
+
+		Convey("Then the most specific domain should be used", func() {
+			So(hostRateLimit("example.com"), ShouldEqual, 2)
+			So(hostRateLimit("cdn.example.com"), ShouldEqual, 2)
+			So(hostRateLimit("v1.api.example.com"), ShouldEqual, 5)
+			So(hostRateLimit("free.example.com"), ShouldEqual, 0)
+		})
+
+		Convey("Then other hosts should use the default limit", func() {
+			So(hostRateLimit("example.org"), ShouldEqual, 0)
+			viper.Set(key.NetworkRateLimit, 10)
+			So(hostRateLimit("example.org"), ShouldEqual, 10)
+		})
+	})
+}
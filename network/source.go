@@ -0,0 +1,39 @@
+package network
+
+import (
+	"context"
+	"net/http"
+)
+
+type sourceKey struct{}
+
+// WithSource marks the requests made with the context as the ones of the source with the given name,
+// so that the settings of the source, such as its proxy, are applied to them
+func WithSource(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, sourceKey{}, name)
+}
+
+// sourceOf returns the name of the source the request is made by
+func sourceOf(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(sourceKey{}).(string)
+	return name, ok
+}
+
+// sourceTransport makes requests on behalf of the source
+type sourceTransport struct {
+	name string
+}
+
+// SourceTransport returns the transport that makes requests on behalf of the source with the given name.
+// It is meant for clients that can't pass the source with the request context, such as the Lua ones
+func SourceTransport(name string) http.RoundTripper {
+	return &sourceTransport{name: name}
+}
+
+func (t *sourceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := sourceOf(req.Context()); !ok {
+		req = req.WithContext(WithSource(req.Context(), t.name))
+	}
+
+	return Transport.RoundTrip(req)
+}
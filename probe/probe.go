@@ -0,0 +1,240 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/preetbiswas12/Kage/provider"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/preetbiswas12/Kage/util"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Stages of the probe, in the order they are run
+const (
+	StageLoad     = "load"
+	StageSearch   = "search"
+	StageChapters = "chapters"
+	StagePages    = "pages"
+	StageImage    = "image"
+)
+
+// Stages are all the stages of the probe
+var Stages = []string{StageLoad, StageSearch, StageChapters, StagePages, StageImage}
+
+// candidates is the number of found mangas to try until one with chapters is found
+const candidates = 3
+
+// Stage is the result of a single probe stage.
+type Stage struct {
+	// Name of the stage
+	Name string `json:"name"`
+	// Passed is true if the stage succeeded
+	Passed bool `json:"passed"`
+	// Skipped is true if the stage was not run because a previous stage failed
+	Skipped bool `json:"skipped"`
+	// Duration of the stage
+	Duration time.Duration `json:"duration"`
+	// Summary of the stage result, e.g. number of found items
+	Summary string `json:"summary,omitempty"`
+	// Error that made the stage fail
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the result of probing a source.
+type Report struct {
+	// Source name
+	Source string `json:"source"`
+	// IsCustom is true for Lua sources
+	IsCustom bool `json:"custom"`
+	// Passed is true if all the stages passed
+	Passed bool `json:"passed"`
+	// Stages results
+	Stages []*Stage `json:"stages"`
+}
+
+// Run probes the source of the provider.
+// It searches for the query and checks chapters and pages of the first found manga,
+// then downloads the first page. Stages after the first failed one are skipped
+func Run(ctx context.Context, p *provider.Provider, query string) *Report {
+	report := &Report{
+		Source:   p.Name,
+		IsCustom: p.IsCustom,
+	}
+
+	var (
+		src     source.Source
+		mangas  []*source.Manga
+		manga   *source.Manga
+		chapter *source.Chapter
+		page    *source.Page
+	)
+
+	stages := map[string]func() (string, error){
+		StageLoad: func() (string, error) {
+			var err error
+			src, err = p.CreateSource()
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("created source %s", src.ID()), nil
+		},
+		StageSearch: func() (string, error) {
+			var err error
+			mangas, err = source.Search(ctx, src, query)
+			if err != nil {
+				return "", err
+			}
+
+			if err = validateMangas(mangas); err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("found %s", util.Quantify(len(mangas), "manga", "mangas")), nil
+		},
+		StageChapters: func() (string, error) {
+			// some mangas may have no chapters at all, so try a few of them
+			var (
+				chapters []*source.Chapter
+				err      error
+			)
+
+			for _, manga = range mangas[:util.Min(len(mangas), candidates)] {
+				chapters, err = source.ChaptersOf(ctx, src, manga)
+				if err != nil {
+					return "", err
+				}
+
+				if len(chapters) > 0 {
+					break
+				}
+			}
+
+			if err = validateChapters(chapters); err != nil {
+				return "", err
+			}
+
+			chapter = chapters[0]
+			return fmt.Sprintf("found %s of %q", util.Quantify(len(chapters), "chapter", "chapters"), manga.Name), nil
+		},
+		StagePages: func() (string, error) {
+			pages, err := source.PagesOf(ctx, src, chapter)
+			if err != nil {
+				return "", err
+			}
+
+			if err = validatePages(pages); err != nil {
+				return "", err
+			}
+
+			page = pages[0]
+			return fmt.Sprintf("found %s of %q", util.Quantify(len(pages), "page", "pages"), chapter.Name), nil
+		},
+		StageImage: func() (string, error) {
+			if err := page.DownloadContext(ctx); err != nil {
+				return "", err
+			}
+
+			contentType := http.DetectContentType(page.Contents.Bytes())
+			if !strings.HasPrefix(contentType, "image/") {
+				return "", fmt.Errorf("first page is %s, not an image", contentType)
+			}
+
+			return fmt.Sprintf("downloaded %d bytes of %s", page.Size, contentType), nil
+		},
+	}
+
+	report.Passed = true
+	for _, name := range Stages {
+		stage := &Stage{Name: name}
+		report.Stages = append(report.Stages, stage)
+
+		if !report.Passed {
+			stage.Skipped = true
+			continue
+		}
+
+		start := time.Now()
+		summary, err := stages[name]()
+		stage.Duration = time.Since(start)
+
+		if err != nil {
+			stage.Error = err.Error()
+			report.Passed = false
+			continue
+		}
+
+		stage.Passed = true
+		stage.Summary = summary
+	}
+
+	return report
+}
+
+// validateURL checks that the address is an absolute URL
+func validateURL(address string) error {
+	u, err := url.Parse(address)
+	if err != nil {
+		return err
+	}
+
+	if !u.IsAbs() {
+		return fmt.Errorf("%q is not an absolute URL", address)
+	}
+
+	return nil
+}
+
+func validateMangas(mangas []*source.Manga) error {
+	if len(mangas) == 0 {
+		return errors.New("no mangas found")
+	}
+
+	for _, manga := range mangas {
+		if strings.TrimSpace(manga.Name) == "" {
+			return fmt.Errorf("manga #%d has no name", manga.Index)
+		}
+
+		if err := validateURL(manga.URL); err != nil {
+			return fmt.Errorf("manga %q has invalid url: %w", manga.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validateChapters(chapters []*source.Chapter) error {
+	if len(chapters) == 0 {
+		return errors.New("no chapters found")
+	}
+
+	for _, chapter := range chapters {
+		if strings.TrimSpace(chapter.Name) == "" {
+			return fmt.Errorf("chapter #%d has no name", chapter.Index)
+		}
+
+		if err := validateURL(chapter.URL); err != nil {
+			return fmt.Errorf("chapter %q has invalid url: %w", chapter.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validatePages(pages []*source.Page) error {
+	if len(pages) == 0 {
+		return errors.New("no pages found")
+	}
+
+	for _, page := range pages {
+		if err := validateURL(page.URL); err != nil {
+			return fmt.Errorf("page #%d has invalid url: %w", page.Index, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"github.com/preetbiswas12/Kage/provider"
+	"github.com/preetbiswas12/Kage/source"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	Convey("Given mangas returned by a source", t, func() {
+		Convey("When a manga has a relative url", func() {
+			err := validateMangas([]*source.Manga{{Name: "test", URL: "/manga/test"}})
+
+			Convey("Validation should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When a manga has no name", func() {
+			err := validateMangas([]*source.Manga{{URL: "https://example.com/manga/test"}})
+
+			Convey("Validation should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When mangas are valid", func() {
+			err := validateMangas([]*source.Manga{{Name: "test", URL: "https://example.com/manga/test"}})
+
+			Convey("Validation should pass", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestRun(t *testing.T) {
+	Convey("Given a provider that fails to load", t, func() {
+		p := &provider.Provider{
+			Name: "broken",
+			CreateSource: func() (source.Source, error) {
+				return nil, errors.New("broken source")
+			},
+		}
+
+		Convey("When it is probed", func() {
+			report := Run(context.Background(), p, "test")
+
+			Convey("The report should fail and skip the rest of the stages", func() {
+				So(report.Passed, ShouldBeFalse)
+				So(report.Stages, ShouldHaveLength, len(Stages))
+				So(report.Stages[0].Error, ShouldEqual, "broken source")
+				So(report.Stages[1].Skipped, ShouldBeTrue)
+			})
+		})
+	})
+}
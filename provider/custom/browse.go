@@ -0,0 +1,46 @@
+package custom
+
+import (
+	"context"
+	"fmt"
+	"github.com/preetbiswas12/Kage/constant"
+	"github.com/preetbiswas12/Kage/source"
+	lua "github.com/yuin/gopher-lua"
+	"strconv"
+)
+
+// BrowseLists returns all the known lists if the source defines BrowseManga function
+func (s *luaSource) BrowseLists() []source.BrowseList {
+	if s.state.GetGlobal(constant.BrowseMangaFn).Type() != lua.LTFunction {
+		return nil
+	}
+
+	return source.BrowseLists
+}
+
+// BrowsePage lists mangas on the page given by the token.
+// Lists are not cached since they change frequently.
+func (s *luaSource) BrowsePage(ctx context.Context, list source.BrowseList, token string) (*source.SearchPage, error) {
+	page := 1
+	if token != "" {
+		var err error
+		page, err = strconv.Atoi(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page token %q", token)
+		}
+	}
+
+	_, err := s.call(ctx, constant.BrowseMangaFn, lua.LTTable, lua.LString(list), lua.LNumber(page))
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := s.mangasFromTable(constant.BrowseMangaFn, s.state.CheckTable(-1))
+
+	result := &source.SearchPage{Mangas: mangas}
+	if len(mangas) > 0 {
+		result.Next = strconv.Itoa(page + 1)
+	}
+
+	return result, nil
+}
@@ -1,6 +1,7 @@
 package custom
 
 import (
+	"context"
 	"github.com/preetbiswas12/Kage/constant"
 	"github.com/preetbiswas12/Kage/source"
 	lua "github.com/yuin/gopher-lua"
@@ -8,6 +9,10 @@ import (
 )
 
 func (s *luaSource) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
+	return s.ChaptersOfContext(context.Background(), manga)
+}
+
+func (s *luaSource) ChaptersOfContext(ctx context.Context, manga *source.Manga) ([]*source.Chapter, error) {
 	if chapters := s.cache.chapters.Get(manga.URL); chapters.IsPresent() {
 		c := chapters.MustGet()
 		for _, chapter := range c {
@@ -17,7 +22,7 @@ func (s *luaSource) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
 		return c, nil
 	}
 
-	_, err := s.call(constant.MangaChaptersFn, lua.LTTable, lua.LString(manga.URL))
+	_, err := s.call(ctx, constant.MangaChaptersFn, lua.LTTable, lua.LString(manga.URL))
 
 	if err != nil {
 		return nil, err
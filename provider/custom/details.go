@@ -0,0 +1,23 @@
+package custom
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/constant"
+	"github.com/preetbiswas12/Kage/source"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MangaDetails fills the manga metadata with the details returned by the MangaDetails function.
+// Does nothing if the source does not define it.
+func (s *luaSource) MangaDetails(ctx context.Context, manga *source.Manga) error {
+	if s.state.GetGlobal(constant.MangaDetailsFn).Type() != lua.LTFunction {
+		return nil
+	}
+
+	_, err := s.call(ctx, constant.MangaDetailsFn, lua.LTTable, lua.LString(manga.URL))
+	if err != nil {
+		return err
+	}
+
+	return detailsFromTable(s.state.CheckTable(-1), manga)
+}
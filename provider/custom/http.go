@@ -0,0 +1,82 @@
+package custom
+
+import (
+	luahttp "github.com/metafates/mangal-lua-libs/http"
+	luaclient "github.com/metafates/mangal-lua-libs/http/client"
+	"github.com/preetbiswas12/Kage/network"
+	lua "github.com/yuin/gopher-lua"
+	"net/url"
+)
+
+// preloadHTTP replaces the loaders of the http modules,
+// so that the clients created by the source make requests through the network transport on its behalf
+// and share the cookie jar
+func preloadHTTP(state *lua.LState, name string) {
+	for module, loader := range map[string]lua.LGFunction{
+		"http":        luahttp.Loader,
+		"http_client": luaclient.Loader,
+	} {
+		state.PreloadModule(module, withNetworkClient(loader, name))
+	}
+
+	state.PreloadModule("headers", headersLoader)
+}
+
+// headersLoader loads the module with the configured request headers.
+// Requests of the http clients get them anyway, the module is for the ones made otherwise, e.g. by the headless browser
+func headersLoader(L *lua.LState) int {
+	L.Push(L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		// user_agent() returns the configured User-Agent
+		"user_agent": func(L *lua.LState) int {
+			L.Push(lua.LString(network.UserAgent()))
+			return 1
+		},
+		// get(url) returns the table of the headers for the url, including User-Agent
+		"get": func(L *lua.LState) int {
+			u, err := url.Parse(L.CheckString(1))
+			if err != nil {
+				L.ArgError(1, err.Error())
+				return 0
+			}
+
+			headers := L.NewTable()
+			headers.RawSetString("User-Agent", lua.LString(network.UserAgent()))
+			for name, values := range network.HostHeaders(u.Hostname()) {
+				headers.RawSetString(name, lua.LString(values[0]))
+			}
+
+			L.Push(headers)
+			return 1
+		},
+	}))
+
+	return 1
+}
+
+// withNetworkClient wraps the module loader, so that its client constructor uses the network transport.
+// Proxy given to the constructor is ignored, network.source_proxies should be used instead
+func withNetworkClient(loader lua.LGFunction, name string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		n := loader(L)
+
+		module, ok := L.Get(-1).(*lua.LTable)
+		if !ok {
+			return n
+		}
+
+		module.RawSetString("client", L.NewFunction(func(L *lua.LState) int {
+			n := luaclient.New(L)
+
+			if ud, ok := L.Get(-1).(*lua.LUserData); ok {
+				if client, ok := ud.Value.(*luaclient.LuaClient); ok {
+					client.Transport = network.SourceTransport(name)
+					client.Jar = network.Jar
+				}
+			}
+
+			return n
+		}))
+
+		return n
+	}
+}
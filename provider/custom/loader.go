@@ -20,8 +20,11 @@ func LoadSource(path string, validate bool) (source.Source, error) {
 		return nil, err
 	}
 
+	name := util.FileStem(path)
+
 	state := lua.NewState()
 	libs.Preload(state)
+	preloadHTTP(state, name)
 
 	lfunc := state.NewFunctionFromProto(proto)
 	state.Push(lfunc)
@@ -30,8 +33,6 @@ func LoadSource(path string, validate bool) (source.Source, error) {
 		return nil, err
 	}
 
-	name := util.FileStem(path)
-
 	if validate {
 		for _, fn := range mustHave {
 			defined := state.GetGlobal(fn)
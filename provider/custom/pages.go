@@ -1,13 +1,18 @@
 package custom
 
 import (
+	"context"
 	"github.com/preetbiswas12/Kage/constant"
 	"github.com/preetbiswas12/Kage/source"
 	lua "github.com/yuin/gopher-lua"
 )
 
 func (s *luaSource) PagesOf(chapter *source.Chapter) ([]*source.Page, error) {
-	_, err := s.call(constant.ChapterPagesFn, lua.LTTable, lua.LString(chapter.URL))
+	return s.PagesOfContext(context.Background(), chapter)
+}
+
+func (s *luaSource) PagesOfContext(ctx context.Context, chapter *source.Chapter) ([]*source.Page, error) {
+	_, err := s.call(ctx, constant.ChapterPagesFn, lua.LTTable, lua.LString(chapter.URL))
 
 	if err != nil {
 		return nil, err
@@ -1,6 +1,8 @@
 package custom
 
 import (
+	"context"
+	"fmt"
 	"github.com/preetbiswas12/Kage/constant"
 	"github.com/preetbiswas12/Kage/source"
 	lua "github.com/yuin/gopher-lua"
@@ -8,36 +10,151 @@ import (
 )
 
 func (s *luaSource) Search(query string) ([]*source.Manga, error) {
-	if mangas := s.cache.mangas.Get(query); mangas.IsPresent() {
-		m := mangas.MustGet()
-		for _, manga := range m {
-			manga.Source = s
+	return s.SearchContext(context.Background(), query)
+}
+
+func (s *luaSource) SearchContext(ctx context.Context, query string) ([]*source.Manga, error) {
+	return s.search(ctx, constant.SearchMangaFn, query, lua.LString(query))
+}
+
+// SearchPage searches for mangas on the page given by the token.
+// If the source does not define SearchMangaPage function, all results are returned as a single page.
+func (s *luaSource) SearchPage(ctx context.Context, query, token string) (*source.SearchPage, error) {
+	return s.SearchFilteredPage(ctx, query, nil, token)
+}
+
+// SearchFilters returns filters defined by the SearchFilters function.
+// Returns nil if the source does not define it.
+func (s *luaSource) SearchFilters() ([]*source.Filter, error) {
+	if s.filters != nil {
+		return s.filters, nil
+	}
+
+	if s.state.GetGlobal(constant.SearchFiltersFn).Type() != lua.LTFunction {
+		return nil, nil
+	}
+
+	_, err := s.call(context.Background(), constant.SearchFiltersFn, lua.LTTable)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*source.Filter, 0)
+	s.state.CheckTable(-1).ForEach(func(_ lua.LValue, v lua.LValue) {
+		if v.Type() != lua.LTTable {
+			s.state.RaiseError("%s was expected to return a table with tables as values, got %s as a value", constant.SearchFiltersFn, v.Type().String())
+		}
+
+		filter, err := filterFromTable(v.(*lua.LTable))
+		if err != nil {
+			s.state.RaiseError("%s", err.Error())
 		}
 
-		return m, nil
+		filters = append(filters, filter)
+	})
+
+	s.filters = filters
+	return filters, nil
+}
+
+// SearchFilteredPage searches for mangas matching the filters on the page given by the token.
+func (s *luaSource) SearchFilteredPage(ctx context.Context, query string, filters source.Filters, token string) (*source.SearchPage, error) {
+	var args []lua.LValue
+	if len(filters) > 0 {
+		definitions, err := s.SearchFilters()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, filtersToTable(s.state, filters, definitions))
 	}
 
-	_, err := s.call(constant.SearchMangaFn, lua.LTTable, lua.LString(query))
+	if s.state.GetGlobal(constant.SearchMangaPageFn).Type() != lua.LTFunction {
+		if token != "" {
+			return &source.SearchPage{}, nil
+		}
+
+		if len(filters) == 0 {
+			mangas, err := s.SearchContext(ctx, query)
+			if err != nil {
+				return nil, err
+			}
 
+			return &source.SearchPage{Mangas: mangas}, nil
+		}
+
+		mangas, err := s.search(ctx, constant.SearchMangaFn, query+"?"+filters.Encode(), append([]lua.LValue{lua.LString(query)}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &source.SearchPage{Mangas: mangas}, nil
+	}
+
+	page := 1
+	if token != "" {
+		var err error
+		page, err = strconv.Atoi(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid search page token %q", token)
+		}
+	}
+
+	cacheKey := fmt.Sprintf("%s#%d", query, page)
+	if len(filters) > 0 {
+		cacheKey += "?" + filters.Encode()
+	}
+
+	mangas, err := s.search(ctx, constant.SearchMangaPageFn, cacheKey, append([]lua.LValue{lua.LString(query), lua.LNumber(page)}, args...)...)
 	if err != nil {
 		return nil, err
 	}
 
-	table := s.state.CheckTable(-1)
+	result := &source.SearchPage{Mangas: mangas}
+	if len(mangas) > 0 {
+		result.Next = strconv.Itoa(page + 1)
+	}
+
+	return result, nil
+}
+
+// search calls the given search function and caches its result under the cache key
+func (s *luaSource) search(ctx context.Context, fn, cacheKey string, args ...lua.LValue) ([]*source.Manga, error) {
+	if cached := s.cache.mangas.Get(cacheKey); cached.IsPresent() {
+		mangas := cached.MustGet()
+		for _, manga := range mangas {
+			manga.Source = s
+		}
+
+		return mangas, nil
+	}
+
+	_, err := s.call(ctx, fn, lua.LTTable, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	mangas := s.mangasFromTable(fn, s.state.CheckTable(-1))
+	_ = s.cache.mangas.Set(cacheKey, mangas)
+	return mangas, nil
+}
+
+// mangasFromTable converts the table returned by the given function to mangas
+func (s *luaSource) mangasFromTable(fn string, table *lua.LTable) []*source.Manga {
 	mangas := make([]*source.Manga, 0)
 
 	table.ForEach(func(k lua.LValue, v lua.LValue) {
 		if k.Type() != lua.LTNumber {
-			s.state.RaiseError("%s was expected to return a table with numbers as keys, got %s as a key", constant.SearchMangaFn, k.Type().String())
+			s.state.RaiseError("%s was expected to return a table with numbers as keys, got %s as a key", fn, k.Type().String())
 		}
 
 		if v.Type() != lua.LTTable {
-			s.state.RaiseError("%s was expected to return a table with tables as values, got %s as a value", constant.SearchMangaFn, v.Type().String())
+			s.state.RaiseError("%s was expected to return a table with tables as values, got %s as a value", fn, v.Type().String())
 		}
 
 		index, err := strconv.ParseUint(k.String(), 10, 16)
 		if err != nil {
-			s.state.RaiseError("%s was expected to return a table with unsigned integers as keys. %s", constant.SearchMangaFn, err.Error())
+			s.state.RaiseError("%s was expected to return a table with unsigned integers as keys. %s", fn, err.Error())
 		}
 
 		manga, err := mangaFromTable(v.(*lua.LTable), uint16(index))
@@ -50,6 +167,5 @@ func (s *luaSource) Search(query string) ([]*source.Manga, error) {
 		mangas = append(mangas, manga)
 	})
 
-	_ = s.cache.mangas.Set(query, mangas)
-	return mangas, nil
+	return mangas
 }
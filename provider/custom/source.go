@@ -1,15 +1,17 @@
 package custom
 
 import (
+	"context"
 	"fmt"
 	"github.com/preetbiswas12/Kage/source"
 	lua "github.com/yuin/gopher-lua"
 )
 
 type luaSource struct {
-	name  string
-	state *lua.LState
-	cache struct {
+	name    string
+	state   *lua.LState
+	filters []*source.Filter
+	cache   struct {
 		mangas   *cacher[[]*source.Manga]
 		chapters *cacher[[]*source.Chapter]
 	}
@@ -35,7 +37,12 @@ func newLuaSource(name string, state *lua.LState) (*luaSource, error) {
 	return s, nil
 }
 
-func (s *luaSource) call(fn string, ret lua.LValueType, args ...lua.LValue) (lua.LValue, error) {
+// call the global Lua function.
+// Running Lua code is interrupted once the context is done.
+func (s *luaSource) call(ctx context.Context, fn string, ret lua.LValueType, args ...lua.LValue) (lua.LValue, error) {
+	s.state.SetContext(ctx)
+	defer s.state.RemoveContext()
+
 	err := s.state.CallByParam(lua.P{
 		Fn:      s.state.GetGlobal(fn),
 		NRet:    1,
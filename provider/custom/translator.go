@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type mapping lo.Tuple4[lua.LValueType, bool, func(string) error, string]
@@ -89,9 +90,42 @@ func chapterFromTable(table *lua.LTable, manga *source.Manga, index uint16) (cha
 	}
 
 	mappings := map[string]mapping{
-		"name":          {A: lua.LTString, B: true, C: func(v string) error { chapter.Name = v; return nil }},
-		"url":           {A: lua.LTString, B: true, C: func(v string) error { chapter.URL = v; return nil }},
-		"volume":        {A: lua.LTString, B: false, C: func(v string) error { chapter.Volume = v; return nil }},
+		"name":     {A: lua.LTString, B: true, C: func(v string) error { chapter.Name = v; return nil }},
+		"url":      {A: lua.LTString, B: true, C: func(v string) error { chapter.URL = v; return nil }},
+		"volume":   {A: lua.LTString, B: false, C: func(v string) error { chapter.Volume = v; return nil }},
+		"language": {A: lua.LTString, B: false, C: func(v string) error { chapter.Language = v; return nil }},
+		"groups": {A: lua.LTString, B: false, C: func(v string) error {
+			if v == "" {
+				return nil
+			}
+
+			chapter.Groups = lo.Map(strings.Split(v, ","), func(group string, _ int) string {
+				return strings.TrimSpace(group)
+			})
+			return nil
+		}},
+		"pages_count": {A: lua.LTNumber, B: false, D: "0", C: func(v string) error {
+			count, err := strconv.Atoi(v)
+			if err != nil {
+				return err
+			}
+
+			chapter.PagesCount = count
+			return nil
+		}},
+		"date": {A: lua.LTString, B: false, C: func(v string) error {
+			if v == "" {
+				return nil
+			}
+
+			date, err := parseDate(v)
+			if err != nil {
+				return err
+			}
+
+			chapter.PublishedAt = date
+			return nil
+		}},
 		"manga_summary": {A: lua.LTString, B: false, C: func(v string) error { manga.Metadata.Summary = v; return nil }},
 		"manga_genres": {A: lua.LTString, B: false, C: func(v string) error {
 			manga.Metadata.Genres = lo.Map(strings.Split(v, ","), func(genre string, _ int) string {
@@ -114,10 +148,92 @@ func chapterFromTable(table *lua.LTable, manga *source.Manga, index uint16) (cha
 	}
 
 	err = translate(table, mappings)
+
+	// number may be given either as a string or as a number
+	switch number := table.RawGetString("number"); number.Type() {
+	case lua.LTNil:
+	case lua.LTString, lua.LTNumber:
+		chapter.Number = source.NormalizeChapterNumber(number.String())
+	default:
+		err = fmt.Errorf(`field of "number" must be of type %s or %s`, lua.LTString, lua.LTNumber)
+	}
+
+	if chapter.Number == "" {
+		chapter.Number = source.ParseChapterNumber(chapter.Name)
+	}
+
 	manga.Chapters = append(manga.Chapters, chapter)
 	return
 }
 
+// dateLayouts are the layouts accepted for dates returned by the Lua sources
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// parseDate parses the date in any of the dateLayouts
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf(`invalid date "%s", expected format YYYY-MM-DD`, value)
+}
+
+// detailsFromTable fills the manga metadata with the details from the table.
+// Missing fields leave the metadata untouched
+func detailsFromTable(table *lua.LTable, manga *source.Manga) error {
+	split := func(v string) []string {
+		return lo.Map(strings.Split(v, ","), func(item string, _ int) string {
+			return strings.TrimSpace(item)
+		})
+	}
+
+	text := func(field *string) func(string) error {
+		return func(v string) error {
+			if v != "" {
+				*field = v
+			}
+
+			return nil
+		}
+	}
+
+	list := func(field *[]string) func(string) error {
+		return func(v string) error {
+			if v != "" {
+				*field = split(v)
+			}
+
+			return nil
+		}
+	}
+
+	mappings := map[string]mapping{
+		"summary": {A: lua.LTString, B: false, C: text(&manga.Metadata.Summary)},
+		"status":  {A: lua.LTString, B: false, C: text(&manga.Metadata.Status)},
+		"authors": {A: lua.LTString, B: false, C: list(&manga.Metadata.Staff.Story)},
+		"artists": {A: lua.LTString, B: false, C: list(&manga.Metadata.Staff.Art)},
+		"genres":  {A: lua.LTString, B: false, C: list(&manga.Metadata.Genres)},
+		"tags":    {A: lua.LTString, B: false, C: list(&manga.Metadata.Tags)},
+		"cover": {A: lua.LTString, B: false, C: func(v string) error {
+			if v == "" {
+				return nil
+			}
+
+			_, err := url.Parse(v)
+			if err != nil {
+				return err
+			}
+
+			manga.Metadata.Cover.ExtraLarge = v
+			return nil
+		}},
+	}
+
+	return translate(table, mappings)
+}
+
 func pageFromTable(table *lua.LTable, chapter *source.Chapter) (page *source.Page, err error) {
 	page = &source.Page{
 		Chapter: chapter,
@@ -145,3 +261,86 @@ func pageFromTable(table *lua.LTable, chapter *source.Chapter) (page *source.Pag
 	chapter.Pages = append(chapter.Pages, page)
 	return
 }
+
+func filterFromTable(table *lua.LTable) (filter *source.Filter, err error) {
+	filter = &source.Filter{}
+
+	mappings := map[string]mapping{
+		"key":  {A: lua.LTString, B: true, C: func(v string) error { filter.Key = v; return nil }},
+		"name": {A: lua.LTString, B: false, C: func(v string) error { filter.Name = v; return nil }},
+		"multiple": {A: lua.LTBool, B: false, D: "false", C: func(v string) error {
+			filter.Multiple = v == "true"
+			return nil
+		}},
+	}
+
+	if err = translate(table, mappings); err != nil {
+		return
+	}
+
+	if filter.Name == "" {
+		filter.Name = filter.Key
+	}
+
+	options := table.RawGetString("options")
+	switch options.Type() {
+	case lua.LTNil:
+		return
+	case lua.LTTable:
+	default:
+		return nil, fmt.Errorf(`field of "options" must be of type %s`, lua.LTTable)
+	}
+
+	options.(*lua.LTable).ForEach(func(_ lua.LValue, v lua.LValue) {
+		if err != nil {
+			return
+		}
+
+		switch v := v.(type) {
+		case lua.LString:
+			filter.Options = append(filter.Options, source.FilterOption{Name: string(v), Value: string(v)})
+		case *lua.LTable:
+			option := source.FilterOption{}
+			err = translate(v, map[string]mapping{
+				"value": {A: lua.LTString, B: true, C: func(v string) error { option.Value = v; return nil }},
+				"name":  {A: lua.LTString, B: false, C: func(v string) error { option.Name = v; return nil }},
+			})
+
+			if option.Name == "" {
+				option.Name = option.Value
+			}
+
+			filter.Options = append(filter.Options, option)
+		default:
+			err = fmt.Errorf("filter options must be strings or tables, got %s", v.Type())
+		}
+	})
+
+	return
+}
+
+// filtersToTable converts filters to the table passed to the Lua functions.
+// Values of filters that accept multiple values are passed as arrays, otherwise as strings
+func filtersToTable(state *lua.LState, filters source.Filters, definitions []*source.Filter) *lua.LTable {
+	table := state.NewTable()
+
+	for k, values := range filters {
+		definition, ok := lo.Find(definitions, func(definition *source.Filter) bool {
+			return definition.Key == k
+		})
+
+		if ok && !definition.Multiple {
+			table.RawSetString(k, lua.LString(filters.Get(k)))
+			continue
+		}
+
+		array := state.NewTable()
+		for _, value := range values {
+			array.Append(lua.LString(value))
+		}
+
+		table.RawSetString(k, array)
+	}
+
+	return table
+}
@@ -0,0 +1,257 @@
+package declarative
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/provider/generic"
+	"github.com/preetbiswas12/Kage/util"
+	"go.yaml.in/yaml/v3"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Extensions of the declarative scraper files.
+// JSON is a subset of YAML, so both are decoded the same way
+var Extensions = []string{".yaml", ".yml", ".json"}
+
+// UnmarshalYAML allows fields to be given as a plain selector,
+// e.g. `name: "a.title"` instead of `name: { selector: "a.title" }`
+func (f *Field) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		f.Selector = value.Value
+		return nil
+	}
+
+	type plain Field
+	return value.Decode((*plain)(f))
+}
+
+// Load reads the scraper description from the file and creates its configuration
+func Load(path string) (*generic.Configuration, error) {
+	contents, err := filesystem.Api().ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if err = yaml.Unmarshal(contents, &spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	conf, err := spec.Configuration(util.FileStem(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	return conf, nil
+}
+
+// Configuration validates the spec and converts it to the generic scraper configuration with the given name
+func (s *Spec) Configuration(name string) (*generic.Configuration, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+
+	var delay time.Duration
+	if s.Delay != "" {
+		var err error
+		delay, err = time.ParseDuration(s.Delay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delay: %w", err)
+		}
+	}
+
+	parallelism := s.Parallelism
+	if parallelism == 0 {
+		parallelism = 1
+	}
+
+	conf := &generic.Configuration{
+		Name:            name,
+		Custom:          true,
+		Delay:           delay,
+		Parallelism:     parallelism,
+		ReverseChapters: s.ReverseChapters,
+		MaxChapterPages: s.MaxChapterPages,
+		BaseURL:         s.BaseURL,
+		GenerateSearchURL: func(query string) string {
+			return strings.ReplaceAll(s.SearchURL, "{query}", url.QueryEscape(strings.TrimSpace(query)))
+		},
+		BrowseURLs: s.Browse,
+	}
+
+	if s.Manga.JSON {
+		conf.MangaJSONExtractor = s.Manga.jsonExtractor()
+	} else {
+		conf.MangaExtractor = s.Manga.extractor()
+	}
+
+	if s.Chapter.JSON {
+		conf.ChapterJSONExtractor = s.Chapter.jsonExtractor()
+	} else {
+		conf.ChapterExtractor = s.Chapter.extractor()
+	}
+
+	if s.ChapterNextPage != nil {
+		if s.Chapter.JSON {
+			conf.ChapterJSONExtractor.Next = s.ChapterNextPage.URL.json()
+		} else {
+			conf.ChapterNextPageExtractor = s.ChapterNextPage.extractor()
+		}
+	}
+
+	if s.Page.JSON {
+		conf.PageJSONExtractor = s.Page.jsonExtractor()
+	} else {
+		conf.PageExtractor = s.Page.extractor()
+	}
+
+	if s.NextPage != nil {
+		if s.Manga.JSON {
+			conf.MangaJSONExtractor.Next = s.NextPage.URL.json()
+		} else {
+			conf.MangaNextPageExtractor = s.NextPage.extractor()
+		}
+	}
+
+	if d := s.Details; d != nil {
+		conf.DetailsExtractor = &generic.DetailsExtractor{
+			Selector: d.Selector,
+			Summary:  d.Summary.text(),
+			Authors:  d.Authors.list(),
+			Artists:  d.Artists.list(),
+			Genres:   d.Genres.list(),
+			Tags:     d.Tags.list(),
+			Status:   d.Status.text(),
+			Cover:    d.Cover.text(),
+		}
+	}
+
+	return conf, nil
+}
+
+func (e *Extractor) extractor() *generic.Extractor {
+	return &generic.Extractor{
+		Selector: e.Selector,
+		Name:     e.Name.text(),
+		URL:      e.URL.text(),
+		Volume:   e.Volume.text(),
+		Cover:    e.Cover.text(),
+	}
+}
+
+func (e *Extractor) jsonExtractor() *generic.JSONExtractor {
+	extractor := &generic.JSONExtractor{
+		Path:   e.Selector,
+		Name:   e.Name.json(),
+		URL:    e.URL.json(),
+		Volume: e.Volume.json(),
+		Cover:  e.Cover.json(),
+	}
+
+	if e.Endpoint != "" {
+		extractor.Endpoint = func(address string) string {
+			id := address
+			if u, err := url.Parse(address); err == nil {
+				id = path.Base(u.Path)
+			}
+
+			return strings.NewReplacer("{url}", address, "{id}", id).Replace(e.Endpoint)
+		}
+	}
+
+	return extractor
+}
+
+// value extracts the value of the field from the element
+func (f *Field) value(selection *goquery.Selection) string {
+	if f.Attr != "" {
+		return f.match(selection.AttrOr(f.Attr, ""))
+	}
+
+	return f.match(selection.Text())
+}
+
+// match applies the regex of the field to the value
+func (f *Field) match(value string) string {
+	value = strings.TrimSpace(value)
+
+	if f.regex != nil {
+		groups := f.regex.FindStringSubmatch(value)
+		switch len(groups) {
+		case 0:
+			return ""
+		case 1:
+			return groups[0]
+		default:
+			return strings.TrimSpace(groups[1])
+		}
+	}
+
+	return value
+}
+
+// text returns a function that extracts the field from the first matching element.
+// Missing fields always extract an empty string
+func (f *Field) text() func(*goquery.Selection) string {
+	if f == nil {
+		return func(*goquery.Selection) string { return "" }
+	}
+
+	return func(selection *goquery.Selection) string {
+		if f.Selector != "" {
+			selection = selection.Find(f.Selector).First()
+		}
+
+		return f.value(selection)
+	}
+}
+
+// json returns a function that extracts the field from the JSON item.
+// Returns nil for missing fields, so that they are not extracted at all
+func (f *Field) json() func(any) string {
+	if f == nil {
+		return nil
+	}
+
+	extract := generic.JSONString(f.Selector)
+	return func(item any) string {
+		return f.match(extract(item))
+	}
+}
+
+// list returns a function that extracts the field from all the matching elements.
+// Returns nil for missing fields, so that they are not extracted at all
+func (f *Field) list() func(*goquery.Selection) []string {
+	if f == nil {
+		return nil
+	}
+
+	return func(selection *goquery.Selection) []string {
+		if f.Selector != "" {
+			selection = selection.Find(f.Selector)
+		}
+
+		var values []string
+		selection.Each(func(_ int, element *goquery.Selection) {
+			value := f.value(element)
+
+			parts := []string{value}
+			if f.Split != "" {
+				parts = strings.Split(value, f.Split)
+			}
+
+			for _, part := range parts {
+				if part = strings.TrimSpace(part); part != "" {
+					values = append(values, part)
+				}
+			}
+		})
+
+		return values
+	}
+}
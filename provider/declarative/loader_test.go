@@ -0,0 +1,147 @@
+package declarative
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/provider/generic"
+	. "github.com/smartystreets/goconvey/convey"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = `
+base_url: https://example.com
+search_url: https://example.com/search?q={query}
+delay: 100ms
+manga:
+  selector: div.manga
+  name: a.title
+  url: { selector: a.title, attr: href }
+chapter:
+  selector: li a
+  name: { regex: 'Chapter\s+([\d.]+)' }
+  url: { attr: href }
+page:
+  selector: img
+  url: { attr: src }
+details:
+  genres: { selector: span.genres, split: "," }
+`
+
+const testPage = `
+<html><body>
+<div class="manga"><a class="title" href="/manga/1"> First </a></div>
+<ul><li><a href="/chapter/1">Read Chapter 12.5 now</a></li></ul>
+<span class="genres">Action, Comedy</span>
+</body></html>
+`
+
+func TestLoad(t *testing.T) {
+	Convey("Given a declarative scraper file", t, func() {
+		filesystem.SetMemMapFs()
+		path := filepath.Join("sources", "example.yaml")
+		So(filesystem.Api().WriteFile(path, []byte(testSpec), 0644), ShouldBeNil)
+
+		Convey("When it is loaded", func() {
+			conf, err := Load(path)
+
+			Convey("The configuration should be created", func() {
+				So(err, ShouldBeNil)
+				So(conf.Name, ShouldEqual, "example")
+				So(conf.Custom, ShouldBeTrue)
+				So(conf.GenerateSearchURL("one piece"), ShouldEqual, "https://example.com/search?q=one+piece")
+			})
+
+			Convey("Extractors should extract values from the page", func() {
+				document, err := goquery.NewDocumentFromReader(strings.NewReader(testPage))
+				So(err, ShouldBeNil)
+
+				manga := document.Find(conf.MangaExtractor.Selector)
+				So(conf.MangaExtractor.Name(manga), ShouldEqual, "First")
+				So(conf.MangaExtractor.URL(manga), ShouldEqual, "/manga/1")
+				So(conf.MangaExtractor.Cover(manga), ShouldBeEmpty)
+
+				chapter := document.Find(conf.ChapterExtractor.Selector)
+				So(conf.ChapterExtractor.Name(chapter), ShouldEqual, "12.5")
+
+				So(conf.DetailsExtractor.Genres(document.Selection), ShouldResemble, []string{"Action", "Comedy"})
+				So(conf.DetailsExtractor.Authors, ShouldBeNil)
+			})
+		})
+
+		Convey("When the required fields are missing", func() {
+			So(filesystem.Api().WriteFile(path, []byte("base_url: https://example.com"), 0644), ShouldBeNil)
+			_, err := Load(path)
+
+			Convey("It should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+const testJSONSpec = `
+base_url: https://example.com
+search_url: https://api.example.com/search?q={query}
+manga:
+  json: true
+  selector: results
+  name: title
+  url: link
+next_page:
+  url: next
+chapter:
+  json: true
+  endpoint: https://api.example.com/manga/{id}/chapters
+  selector: data.*
+  name: { selector: title, regex: 'Chapter\s+([\d.]+)' }
+  url: url
+chapter_next_page:
+  url: links.next
+max_chapter_pages: 5
+page:
+  selector: img
+  url: { attr: src }
+`
+
+func TestLoad_JSON(t *testing.T) {
+	Convey("Given a declarative scraper file with JSON stages", t, func() {
+		filesystem.SetMemMapFs()
+		path := filepath.Join("sources", "example.yaml")
+		So(filesystem.Api().WriteFile(path, []byte(testJSONSpec), 0644), ShouldBeNil)
+
+		Convey("When it is loaded", func() {
+			conf, err := Load(path)
+			So(err, ShouldBeNil)
+
+			Convey("JSON extractors should replace HTML ones of their stages only", func() {
+				So(conf.MangaExtractor, ShouldBeNil)
+				So(conf.MangaJSONExtractor, ShouldNotBeNil)
+				So(conf.ChapterExtractor, ShouldBeNil)
+				So(conf.ChapterJSONExtractor, ShouldNotBeNil)
+				So(conf.PageExtractor, ShouldNotBeNil)
+				So(conf.PageJSONExtractor, ShouldBeNil)
+			})
+
+			Convey("Extractors should extract values from the response", func() {
+				response := map[string]any{
+					"results": []any{map[string]any{"title": " First ", "link": "/manga/1"}},
+					"next":    "/search?page=2",
+				}
+
+				item := generic.JSONPath(response, conf.MangaJSONExtractor.Path+".0")[0]
+				So(conf.MangaJSONExtractor.Name(item), ShouldEqual, "First")
+				So(conf.MangaJSONExtractor.URL(item), ShouldEqual, "/manga/1")
+				So(conf.MangaJSONExtractor.Cover, ShouldBeNil)
+				So(conf.MangaJSONExtractor.Next(response), ShouldEqual, "/search?page=2")
+
+				chapter := map[string]any{"title": "Read Chapter 12.5 now"}
+				So(conf.ChapterJSONExtractor.Name(chapter), ShouldEqual, "12.5")
+				So(conf.ChapterJSONExtractor.Endpoint("https://example.com/manga/42"), ShouldEqual, "https://api.example.com/manga/42/chapters")
+				So(conf.ChapterJSONExtractor.Next(map[string]any{"links": map[string]any{"next": "/page/2"}}), ShouldEqual, "/page/2")
+				So(conf.MaxChapterPages, ShouldEqual, 5)
+			})
+		})
+	})
+}
@@ -0,0 +1,161 @@
+package declarative
+
+import (
+	"errors"
+	"fmt"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"regexp"
+	"strings"
+)
+
+// Field describes how to extract a value from an element.
+type Field struct {
+	// Selector of the element relative to the matched one. Optional, the matched element itself is used if empty.
+	// For JSON extractors it is the path of the value relative to the matched item
+	Selector string `yaml:"selector"`
+	// Attr is the attribute to take the value from. Optional, the element text is used if empty. Not used by JSON extractors
+	Attr string `yaml:"attr"`
+	// Regex to apply to the value. Optional.
+	// The first capture group is used as the value, or the whole match if there are no groups
+	Regex string `yaml:"regex"`
+	// Split is the separator of the values in a single element. Used by list fields only. Optional
+	Split string `yaml:"split"`
+
+	regex *regexp.Regexp
+}
+
+// Extractor describes how to find elements and extract data from them.
+type Extractor struct {
+	// Selector of the elements. For JSON extractors it is the path of the items, empty for the whole response
+	Selector string `yaml:"selector"`
+	// JSON if true, the extractor reads the JSON response instead of the HTML page
+	JSON bool `yaml:"json"`
+	// Endpoint is the address of the JSON, where {url} is replaced with the manga or chapter address
+	// and {id} with its last path segment. Used by JSON chapter and page extractors. Optional
+	Endpoint string `yaml:"endpoint"`
+	// Name of the manga or chapter
+	Name *Field `yaml:"name"`
+	// URL of the manga, chapter or page
+	URL *Field `yaml:"url"`
+	// Volume of the chapter. Used by chapter extractor
+	Volume *Field `yaml:"volume"`
+	// Cover of the manga. Used by manga extractor
+	Cover *Field `yaml:"cover"`
+}
+
+// DetailsExtractor describes how to extract manga details from the manga page.
+type DetailsExtractor struct {
+	// Selector of the element containing details. Optional, the whole page is used if empty
+	Selector string `yaml:"selector"`
+	Summary  *Field `yaml:"summary"`
+	Authors  *Field `yaml:"authors"`
+	Artists  *Field `yaml:"artists"`
+	Genres   *Field `yaml:"genres"`
+	Tags     *Field `yaml:"tags"`
+	Status   *Field `yaml:"status"`
+	Cover    *Field `yaml:"cover"`
+}
+
+// Spec is a declarative description of a scraper.
+// Name of the scraper is the name of its file, same as for Lua sources
+type Spec struct {
+	// BaseURL of the source
+	BaseURL string `yaml:"base_url"`
+	// SearchURL is the search address, where {query} is replaced with the escaped query
+	SearchURL string `yaml:"search_url"`
+	// Delay between requests, e.g. "100ms"
+	Delay string `yaml:"delay"`
+	// Parallelism is the maximum number of concurrent requests
+	Parallelism uint8 `yaml:"parallelism"`
+	// ReverseChapters if true, chapters will be shown in reverse order
+	ReverseChapters bool `yaml:"reverse_chapters"`
+	// Browse are addresses of the manga listings by their names: latest, popular or recent
+	Browse map[source.BrowseList]string `yaml:"browse"`
+
+	// MaxChapterPages is the maximum number of the chapters list pages followed. Optional
+	MaxChapterPages uint16 `yaml:"max_chapter_pages"`
+
+	Manga           *Extractor        `yaml:"manga"`
+	NextPage        *Extractor        `yaml:"next_page"`
+	Chapter         *Extractor        `yaml:"chapter"`
+	ChapterNextPage *Extractor        `yaml:"chapter_next_page"`
+	Page            *Extractor        `yaml:"page"`
+	Details         *DetailsExtractor `yaml:"details"`
+}
+
+// validate checks that the required fields are present and compiles the regexes
+func (s *Spec) validate() error {
+	if s.BaseURL == "" {
+		return errors.New("base_url is required")
+	}
+
+	if !strings.Contains(s.SearchURL, "{query}") {
+		return errors.New(`search_url is required and must contain "{query}"`)
+	}
+
+	for list := range s.Browse {
+		if !lo.Contains(source.BrowseLists, list) {
+			return fmt.Errorf("unknown browse list %q", list)
+		}
+	}
+
+	for name, extractor := range map[string]*Extractor{
+		"manga":   s.Manga,
+		"chapter": s.Chapter,
+		"page":    s.Page,
+	} {
+		if extractor == nil || (extractor.Selector == "" && !extractor.JSON) {
+			return fmt.Errorf("%s.selector is required", name)
+		}
+
+		if extractor.URL == nil {
+			return fmt.Errorf("%s.url is required", name)
+		}
+	}
+
+	if s.Manga.Endpoint != "" {
+		return errors.New("manga.endpoint is not supported, search_url is requested instead")
+	}
+
+	// next page of JSON results is found in the whole response
+	if s.NextPage != nil && ((s.NextPage.Selector == "" && !s.Manga.JSON) || s.NextPage.URL == nil) {
+		return errors.New("next_page.selector and next_page.url are required")
+	}
+
+	if s.ChapterNextPage != nil && ((s.ChapterNextPage.Selector == "" && !s.Chapter.JSON) || s.ChapterNextPage.URL == nil) {
+		return errors.New("chapter_next_page.selector and chapter_next_page.url are required")
+	}
+
+	return s.compile()
+}
+
+// compile compiles regexes of all the fields
+func (s *Spec) compile() error {
+	var fields []*Field
+
+	for _, extractor := range []*Extractor{s.Manga, s.NextPage, s.Chapter, s.ChapterNextPage, s.Page} {
+		if extractor != nil {
+			fields = append(fields, extractor.Name, extractor.URL, extractor.Volume, extractor.Cover)
+		}
+	}
+
+	if d := s.Details; d != nil {
+		fields = append(fields, d.Summary, d.Authors, d.Artists, d.Genres, d.Tags, d.Status, d.Cover)
+	}
+
+	for _, field := range fields {
+		if field == nil || field.Regex == "" {
+			continue
+		}
+
+		regex, err := regexp.Compile(field.Regex)
+		if err != nil {
+			return fmt.Errorf("invalid regex %q: %w", field.Regex, err)
+		}
+
+		field.regex = regex
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package generic
+
+import (
+	"context"
+	"fmt"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+)
+
+// BrowseLists returns lists that have browse URLs in the configuration
+func (s *Scraper) BrowseLists() []source.BrowseList {
+	return lo.Filter(source.BrowseLists, func(list source.BrowseList, _ int) bool {
+		_, ok := s.config.BrowseURLs[list]
+		return ok
+	})
+}
+
+// BrowsePage lists mangas on the page given by the token.
+// Token is the URL of the page, empty token stands for the first one.
+func (s *Scraper) BrowsePage(ctx context.Context, list source.BrowseList, token string) (*source.SearchPage, error) {
+	address := token
+	if address == "" {
+		var ok bool
+		address, ok = s.config.BrowseURLs[list]
+		if !ok {
+			return nil, fmt.Errorf("unsupported browse list %s", list)
+		}
+	}
+
+	return s.mangasPage(ctx, address)
+}
@@ -0,0 +1,205 @@
+package generic
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/preetbiswas12/Kage/where"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheStage is the stage of the scraper, each one has its own cache lifetime
+type cacheStage string
+
+const (
+	stageSearch   cacheStage = "search"
+	stageChapters cacheStage = "chapters"
+	stagePages    cacheStage = "pages"
+)
+
+// cacheStages are all the cached stages
+var cacheStages = []cacheStage{stageSearch, stageChapters, stagePages}
+
+// lifetime of the cached responses of the stage
+func (c cacheStage) lifetime() time.Duration {
+	var minutes int
+	switch c {
+	case stageSearch:
+		minutes = viper.GetInt(key.CacheSearchLifetime)
+	case stageChapters:
+		minutes = viper.GetInt(key.CacheChaptersLifetime)
+	case stagePages:
+		minutes = viper.GetInt(key.CachePagesLifetime)
+	}
+
+	return time.Duration(minutes) * time.Minute
+}
+
+type cacheStageKey struct{}
+
+// withStage marks the requests made with the context as the ones of the stage
+func withStage(ctx context.Context, stage cacheStage) context.Context {
+	return context.WithValue(ctx, cacheStageKey{}, stage)
+}
+
+// cacheDir is the directory of the cached responses of all the generic scrapers
+func cacheDir() string {
+	return filepath.Join(where.Cache(), "generic")
+}
+
+// cacheTransport caches successful responses to the GET requests on disk
+// for the lifetime of the stage they were made by
+type cacheTransport struct {
+	// dir of the source cache
+	dir  string
+	next http.RoundTripper
+}
+
+func newCacheTransport(name string, next http.RoundTripper) *cacheTransport {
+	return &cacheTransport{
+		dir:  filepath.Join(cacheDir(), util.SanitizeFilename(name)),
+		next: next,
+	}
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stage, ok := req.Context().Value(cacheStageKey{}).(cacheStage)
+	if !ok || req.Method != http.MethodGet || stage.lifetime() <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	path := t.path(stage, req)
+	if !viper.GetBool(key.CacheRefresh) {
+		if resp, ok := t.load(path, req, stage.lifetime()); ok {
+			return resp, nil
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	// reads the body and replaces it with the copy
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// failing to cache the response is not an error
+	if err = filesystem.Api().MkdirAll(filepath.Dir(path), os.ModePerm); err == nil {
+		_ = filesystem.Api().WriteFile(path, dump, os.ModePerm)
+	}
+
+	return resp, nil
+}
+
+// path of the cached response to the request
+func (t *cacheTransport) path(stage cacheStage, req *http.Request) string {
+	hash := sha1.Sum([]byte(req.URL.String()))
+	return filepath.Join(t.dir, string(stage), hex.EncodeToString(hash[:]))
+}
+
+// load reads the cached response unless it is expired
+func (t *cacheTransport) load(path string, req *http.Request, lifetime time.Duration) (*http.Response, bool) {
+	info, err := filesystem.Api().Stat(path)
+	if err != nil || time.Since(info.ModTime()) > lifetime {
+		return nil, false
+	}
+
+	dump, err := filesystem.Api().ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(dump)), req)
+	if err != nil {
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// CacheStats are statistics of the cached responses of the stage of a source
+type CacheStats struct {
+	// Source is the name of the source
+	Source string
+	// Stage is either search, chapters or pages
+	Stage string
+	// Entries is the number of the cached responses, including the expired ones
+	Entries int
+	// Expired is the number of the responses older than the stage lifetime
+	Expired int
+	// Size of the cached responses in bytes
+	Size int64
+}
+
+// ReadCacheStats returns statistics of the cached responses of all the generic sources, sorted by source
+func ReadCacheStats() ([]*CacheStats, error) {
+	sources, err := filesystem.Api().ReadDir(cacheDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var stats []*CacheStats
+	for _, src := range sources {
+		if !src.IsDir() {
+			continue
+		}
+
+		for _, stage := range cacheStages {
+			stat := &CacheStats{Source: src.Name(), Stage: string(stage)}
+
+			err = filesystem.Api().Walk(filepath.Join(cacheDir(), src.Name(), string(stage)), func(_ string, info os.FileInfo, err error) error {
+				if err != nil {
+					if os.IsNotExist(err) {
+						return nil
+					}
+
+					return err
+				}
+
+				if info.IsDir() {
+					return nil
+				}
+
+				stat.Entries++
+				stat.Size += info.Size()
+				if time.Since(info.ModTime()) > stage.lifetime() {
+					stat.Expired++
+				}
+
+				return nil
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			if stat.Entries > 0 {
+				stats = append(stats, stat)
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// InvalidateCache removes the cached responses of the source with the given name
+func InvalidateCache(name string) error {
+	return filesystem.Api().RemoveAll(filepath.Join(cacheDir(), util.SanitizeFilename(name)))
+}
@@ -0,0 +1,103 @@
+package generic
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCacheTransport(t *testing.T) {
+	Convey("Given a cache transport", t, func() {
+		filesystem.SetMemMapFs()
+		viper.Set(key.CacheSearchLifetime, 60)
+		viper.Set(key.CacheChaptersLifetime, 0)
+		defer viper.Set(key.CacheRefresh, false)
+
+		var hits int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			if r.URL.Path == "/missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write([]byte("hello"))
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: newCacheTransport("Test Source", http.DefaultTransport)}
+		get := func(stage cacheStage, path string) string {
+			req, err := http.NewRequestWithContext(withStage(context.Background(), stage), http.MethodGet, server.URL+path, nil)
+			So(err, ShouldBeNil)
+
+			resp, err := client.Do(req)
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			return string(body)
+		}
+
+		Convey("When requesting the same page twice", func() {
+			So(get(stageSearch, "/search"), ShouldEqual, "hello")
+			So(get(stageSearch, "/search"), ShouldEqual, "hello")
+
+			Convey("Then the second response should be cached", func() {
+				So(hits, ShouldEqual, 1)
+			})
+
+			Convey("Then it should be counted in the stats", func() {
+				stats, err := ReadCacheStats()
+				So(err, ShouldBeNil)
+				So(stats, ShouldHaveLength, 1)
+				So(stats[0].Source, ShouldEqual, "Test_Source")
+				So(stats[0].Stage, ShouldEqual, "search")
+				So(stats[0].Entries, ShouldEqual, 1)
+				So(stats[0].Expired, ShouldEqual, 0)
+			})
+
+			Convey("And refreshing", func() {
+				viper.Set(key.CacheRefresh, true)
+				So(get(stageSearch, "/search"), ShouldEqual, "hello")
+
+				Convey("Then the page should be requested again", func() {
+					So(hits, ShouldEqual, 2)
+				})
+			})
+
+			Convey("And invalidating the cache", func() {
+				So(InvalidateCache("Test Source"), ShouldBeNil)
+				So(get(stageSearch, "/search"), ShouldEqual, "hello")
+
+				Convey("Then the page should be requested again", func() {
+					So(hits, ShouldEqual, 2)
+				})
+			})
+		})
+
+		Convey("When the stage is not cached", func() {
+			get(stageChapters, "/chapters")
+			get(stageChapters, "/chapters")
+
+			Convey("Then every request should reach the server", func() {
+				So(hits, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When the response is not successful", func() {
+			get(stageSearch, "/missing")
+			get(stageSearch, "/missing")
+
+			Convey("Then it should not be cached", func() {
+				So(hits, ShouldEqual, 2)
+			})
+		})
+	})
+}
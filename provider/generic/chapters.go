@@ -1,6 +1,7 @@
 package generic
 
 import (
+	"context"
 	"github.com/gocolly/colly/v2"
 	"github.com/preetbiswas12/Kage/source"
 	"net/http"
@@ -8,23 +9,52 @@ import (
 
 // ChaptersOf given source.Manga
 func (s *Scraper) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
+	return s.ChaptersOfContext(context.Background(), manga)
+}
+
+// ChaptersOfContext returns chapters of given source.Manga until the context is done
+func (s *Scraper) ChaptersOfContext(ctx context.Context, manga *source.Manga) ([]*source.Chapter, error) {
 	if chapters, ok := s.chapters[manga.URL]; ok {
 		return chapters, nil
 	}
 
-	ctx := colly.NewContext()
-	ctx.Put("manga", manga)
-	err := s.chaptersCollector.Request(http.MethodGet, manga.URL, nil, ctx, nil)
+	collyCtx := colly.NewContext()
+	collyCtx.Put("manga", manga)
+
+	address := manga.URL
+	if s.config.ChapterJSONExtractor != nil {
+		address = s.config.ChapterJSONExtractor.endpoint(address)
+	}
+
+	collector := s.chaptersCollector(ctx)
+	err := collector.Request(http.MethodGet, address, nil, collyCtx, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	s.chaptersCollector.Wait()
+	collector.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = responseError(collyCtx); err != nil {
+		return nil, err
+	}
+
+	// chapters of all the pages are collected, so the whole list can be reversed
+	chapters, ok := collyCtx.GetAny("chapters").([]*source.Chapter)
+	if !ok {
+		return nil, nil
+	}
+
+	if chapters == nil {
+		chapters = make([]*source.Chapter, 0)
+	}
 
 	if s.config.ReverseChapters {
 		// reverse chapters
-		chapters := s.chapters[manga.URL]
 		reversed := make([]*source.Chapter, len(chapters))
 		for i, chapter := range chapters {
 			reversed[len(chapters)-i-1] = chapter
@@ -32,8 +62,10 @@ func (s *Scraper) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
 			chapter.Index++
 		}
 
-		s.chapters[manga.URL] = reversed
+		chapters = reversed
 	}
 
-	return s.chapters[manga.URL], nil
+	s.chapters[manga.URL] = chapters
+	manga.Chapters = chapters
+	return chapters, nil
 }
@@ -0,0 +1,98 @@
+package generic
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScraper_ChaptersPagination(t *testing.T) {
+	Convey("Given a source with the chapters list split into pages", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+
+			// newest chapters first, two per page
+			body := fmt.Sprintf(`<a class="chapter" href="/chapter/%d">Chapter %d</a>`, 7-2*page, 7-2*page)
+			body += fmt.Sprintf(`<a class="chapter" href="/chapter/%d">Chapter %d</a>`, 6-2*page, 6-2*page)
+			if page < 3 {
+				body += fmt.Sprintf(`<a class="next" href="?page=%d">Next</a>`, page+1)
+			} else if strings.HasPrefix(r.URL.Path, "/cycle") {
+				// the last page links back to the first one
+				body += `<a class="next" href="?page=1">Next</a>`
+			}
+
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><body>" + body + "</body></html>"))
+		}))
+		defer server.Close()
+
+		href := func(s *goquery.Selection) string { return s.AttrOr("href", "") }
+		conf := &Configuration{
+			Name:            "test",
+			Parallelism:     1,
+			ReverseChapters: true,
+			ChapterExtractor: &Extractor{
+				Selector: "a.chapter",
+				Name:     func(s *goquery.Selection) string { return s.Text() },
+				URL:      href,
+				Volume:   func(*goquery.Selection) string { return "" },
+			},
+			ChapterNextPageExtractor: &Extractor{
+				Selector: "a.next",
+				URL:      href,
+			},
+		}
+
+		names := func(chapters []*source.Chapter) []string {
+			return lo.Map(chapters, func(chapter *source.Chapter, _ int) string {
+				return fmt.Sprintf("%d:%s", chapter.Index, chapter.Name)
+			})
+		}
+
+		Convey("When getting the chapters", func() {
+			manga := &source.Manga{URL: server.URL + "/manga/1"}
+			chapters, err := New(conf).ChaptersOf(manga)
+			So(err, ShouldBeNil)
+
+			Convey("Then chapters of all the pages should be reversed together", func() {
+				So(names(chapters), ShouldResemble, []string{
+					"1:Chapter 0", "2:Chapter 1", "3:Chapter 2", "4:Chapter 3", "5:Chapter 4", "6:Chapter 5",
+				})
+				So(manga.Chapters, ShouldResemble, chapters)
+			})
+		})
+
+		Convey("When the pagination cycles", func() {
+			chapters, err := New(conf).ChaptersOf(&source.Manga{URL: server.URL + "/cycle/1?page=1"})
+			So(err, ShouldBeNil)
+
+			Convey("Then each page should be collected once", func() {
+				So(names(chapters), ShouldResemble, []string{
+					"1:Chapter 0", "2:Chapter 1", "3:Chapter 2", "4:Chapter 3", "5:Chapter 4", "6:Chapter 5",
+				})
+			})
+		})
+
+		Convey("When the pages are limited", func() {
+			conf.MaxChapterPages = 2
+			chapters, err := New(conf).ChaptersOf(&source.Manga{URL: server.URL + "/manga/2"})
+			So(err, ShouldBeNil)
+
+			Convey("Then only the chapters of the first pages should be collected", func() {
+				So(names(chapters), ShouldResemble, []string{
+					"1:Chapter 2", "2:Chapter 3", "3:Chapter 4", "4:Chapter 5",
+				})
+			})
+		})
+	})
+}
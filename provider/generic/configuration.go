@@ -2,6 +2,7 @@ package generic
 
 import (
 	"github.com/PuerkitoBio/goquery"
+	"github.com/preetbiswas12/Kage/source"
 	"time"
 )
 
@@ -19,10 +20,33 @@ type Extractor struct {
 	Cover func(*goquery.Selection) string
 }
 
+// DetailsExtractor is responsible for extracting manga details from the manga page.
+// All the functions are optional
+type DetailsExtractor struct {
+	// Selector CSS selector of the element containing details
+	Selector string
+	// Summary function to get description of the manga
+	Summary func(*goquery.Selection) string
+	// Authors function to get story authors
+	Authors func(*goquery.Selection) []string
+	// Artists function to get art authors
+	Artists func(*goquery.Selection) []string
+	// Genres function to get genres of the manga
+	Genres func(*goquery.Selection) []string
+	// Tags function to get tags of the manga
+	Tags func(*goquery.Selection) []string
+	// Status function to get status of the manga. Should return one of source.Status* constants
+	Status func(*goquery.Selection) string
+	// Cover function to get cover of the manga
+	Cover func(*goquery.Selection) string
+}
+
 // Configuration is a generic scraper configuration that defines behavior of the scraper
 type Configuration struct {
 	// Name of the scraper
 	Name string
+	// Custom is true for configurations loaded from the sources directory rather than built into the binary
+	Custom bool
 	// Delay between requests
 	Delay time.Duration
 	// Parallelism of the scraper
@@ -37,14 +61,53 @@ type Configuration struct {
 	// E.g. "one piece" -> "https://manganelo.com/search/story/one%20piece"
 	GenerateSearchURL func(query string) string
 
+	// BrowseURLs are addresses of the manga listings, such as latest updates.
+	// Listings are parsed with MangaExtractor and MangaNextPageExtractor. Optional
+	BrowseURLs map[source.BrowseList]string
+
+	// MangaNextPageExtractor is responsible for finding the link to the next page of search results.
+	// Only Selector and URL are used. Optional, only the first page of results is used if nil
+	MangaNextPageExtractor *Extractor
+
+	// ChapterNextPageExtractor is responsible for finding the link to the next page of the chapters list.
+	// Only Selector and URL are used. Optional, only the first page of chapters is used if nil
+	ChapterNextPageExtractor *Extractor
+	// MaxChapterPages limits the number of the chapters list pages followed.
+	// Optional, DefaultMaxChapterPages is used if 0
+	MaxChapterPages uint16
+
 	// MangaExtractor is responsible for finding manga elements and extracting required data from them
 	MangaExtractor,
 	// ChapterExtractor is responsible for finding chapter elements and extracting required data from them
 	ChapterExtractor,
 	// PageExtractor is responsible for finding page elements and extracting required data from them
 	PageExtractor *Extractor
+
+	// MangaJSONExtractor, ChapterJSONExtractor and PageJSONExtractor replace the HTML extractors of their stages,
+	// so that the stage reads a JSON endpoint instead. Optional, stages can mix HTML and JSON
+	MangaJSONExtractor,
+	ChapterJSONExtractor,
+	PageJSONExtractor *JSONExtractor
+
+	// DetailsExtractor is responsible for extracting manga details from the manga page. Optional
+	DetailsExtractor *DetailsExtractor
+}
+
+// DefaultMaxChapterPages is the number of the chapters list pages followed if the configuration has no limit
+const DefaultMaxChapterPages = 100
+
+func (c *Configuration) maxChapterPages() int {
+	if c.MaxChapterPages == 0 {
+		return DefaultMaxChapterPages
+	}
+
+	return int(c.MaxChapterPages)
 }
 
 func (c *Configuration) ID() string {
+	if c.Custom {
+		return c.Name + " custom"
+	}
+
 	return c.Name + " built-in"
 }
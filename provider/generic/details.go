@@ -0,0 +1,88 @@
+package generic
+
+import (
+	"context"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+	"github.com/preetbiswas12/Kage/network"
+	"github.com/preetbiswas12/Kage/source"
+	"strings"
+)
+
+// MangaDetails extracts manga details from the manga page.
+// Does nothing if the configuration has no details extractor.
+func (s *Scraper) MangaDetails(ctx context.Context, manga *source.Manga) error {
+	if s.config.DetailsExtractor == nil {
+		return nil
+	}
+
+	collector := s.detailsCollector(ctx)
+
+	collyCtx := colly.NewContext()
+	collyCtx.Put("manga", manga)
+
+	err := collector.Request("GET", manga.URL, nil, collyCtx, nil)
+	if err != nil {
+		return err
+	}
+
+	collector.Wait()
+	return ctx.Err()
+}
+
+// detailsCollector creates a collector that extracts details on the manga page
+func (s *Scraper) detailsCollector(ctx context.Context) *colly.Collector {
+	// details are on the manga page, same as chapters
+	detailsCollector := s.clone(ctx, stageChapters)
+	detailsCollector.OnRequest(func(r *colly.Request) {
+		r.Headers.Set("Referer", "https://google.com")
+		r.Headers.Set("accept-language", "en-US")
+		r.Headers.Set("Accept", "text/html")
+		r.Headers.Set("User-Agent", network.UserAgent())
+	})
+
+	extractor := s.config.DetailsExtractor
+	detailsCollector.OnHTML("html", func(e *colly.HTMLElement) {
+		manga := e.Request.Ctx.GetAny("manga").(*source.Manga)
+
+		selection := e.DOM
+		if extractor.Selector != "" {
+			selection = e.DOM.Find(extractor.Selector).First()
+		}
+
+		text := func(f func(*goquery.Selection) string) string {
+			if f == nil {
+				return ""
+			}
+
+			return strings.TrimSpace(f(selection))
+		}
+
+		list := func(f func(*goquery.Selection) []string, current []string) []string {
+			if f == nil {
+				return current
+			}
+
+			return f(selection)
+		}
+
+		if summary := text(extractor.Summary); summary != "" {
+			manga.Metadata.Summary = summary
+		}
+
+		if status := text(extractor.Status); status != "" {
+			manga.Metadata.Status = status
+		}
+
+		if cover := text(extractor.Cover); cover != "" {
+			manga.Metadata.Cover.ExtraLarge = e.Request.AbsoluteURL(cover)
+		}
+
+		manga.Metadata.Staff.Story = list(extractor.Authors, manga.Metadata.Staff.Story)
+		manga.Metadata.Staff.Art = list(extractor.Artists, manga.Metadata.Staff.Art)
+		manga.Metadata.Genres = list(extractor.Genres, manga.Metadata.Genres)
+		manga.Metadata.Tags = list(extractor.Tags, manga.Metadata.Tags)
+	})
+
+	return detailsCollector
+}
@@ -0,0 +1,147 @@
+package generic
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gocolly/colly/v2"
+	"github.com/samber/lo"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONExtractor is responsible for finding items in the JSON response and extracting required data from them.
+// Stages with JSON extractors request JSON endpoints instead of parsing HTML pages
+type JSONExtractor struct {
+	// Path to the items in the response, see JSONPath. Empty path stands for the whole response
+	Path string
+	// Endpoint function to get the JSON address from the address of the manga or chapter.
+	// Optional, the address itself is requested if nil. Not used by manga extractor
+	Endpoint func(address string) string
+	// Name function to get name from the item.
+	Name func(item any) string
+	// URL function to get URL from the item.
+	URL func(item any) string
+	// Volume function to get volume from the item. Used by chapters extractor
+	Volume func(item any) string
+	// Cover function to get cover from the item. Used by manga extractor
+	Cover func(item any) string
+	// Next function to get the address of the next page of results from the whole response.
+	// Used by manga and chapters extractors. Optional, only the first page of results is used if nil
+	Next func(response any) string
+}
+
+// endpoint returns the address of the JSON for the manga or chapter address
+func (j *JSONExtractor) endpoint(address string) string {
+	if j.Endpoint == nil {
+		return address
+	}
+
+	return j.Endpoint(address)
+}
+
+// items finds the items in the response
+func (j *JSONExtractor) items(response any) []any {
+	items := JSONPath(response, j.Path)
+
+	// path to the array itself stands for its elements
+	if len(items) == 1 {
+		if array, ok := items[0].([]any); ok {
+			return array
+		}
+	}
+
+	return items
+}
+
+// JSONPath returns the values found by the path in the JSON value.
+// Path consists of object keys and array indexes separated by dots, e.g. "data.chapters.0.title".
+// Wildcard "*" matches all the elements of an array or an object, e.g. "data.*.attributes.title".
+// Object elements are matched in the order of their keys
+func JSONPath(value any, path string) []any {
+	values := []any{value}
+	if path = strings.Trim(path, "."); path == "" {
+		return values
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		var next []any
+
+		for _, v := range values {
+			switch v := v.(type) {
+			case map[string]any:
+				if segment == "*" {
+					// keep the order stable
+					keys := lo.Keys(v)
+					sort.Strings(keys)
+					for _, key := range keys {
+						next = append(next, v[key])
+					}
+				} else if element, ok := v[segment]; ok {
+					next = append(next, element)
+				}
+			case []any:
+				if segment == "*" {
+					next = append(next, v...)
+				} else if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(v) {
+					next = append(next, v[index])
+				}
+			}
+		}
+
+		values = next
+	}
+
+	return values
+}
+
+// JSONString returns a function that gets the first value found by the path as a string.
+// Numbers and booleans are formatted, missing values and objects are empty strings
+func JSONString(path string) func(any) string {
+	return func(value any) string {
+		for _, v := range JSONPath(value, path) {
+			switch v := v.(type) {
+			case string:
+				return strings.TrimSpace(v)
+			case float64:
+				return strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				return strconv.FormatBool(v)
+			}
+		}
+
+		return ""
+	}
+}
+
+// jsonExtract is a shorthand for the optional extractor function
+func jsonExtract(f func(any) string, item any) string {
+	if f == nil {
+		return ""
+	}
+
+	return f(item)
+}
+
+// onJSON registers the callback for the decoded JSON responses.
+// Failed decoding is stored in the request context, see responseError
+func onJSON(collector *colly.Collector, callback func(r *colly.Response, response any)) {
+	collector.OnResponse(func(r *colly.Response) {
+		var response any
+		if err := json.Unmarshal(r.Body, &response); err != nil {
+			r.Ctx.Put("error", fmt.Errorf("invalid JSON response from %s: %w", r.Request.URL, err))
+			return
+		}
+
+		callback(r, response)
+	})
+}
+
+// responseError returns the error of the response handling, if any
+func responseError(ctx *colly.Context) error {
+	if err, ok := ctx.GetAny("error").(error); ok {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package generic
+
+import (
+	"encoding/json"
+	"github.com/PuerkitoBio/goquery"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	Convey("Given a JSON response", t, func() {
+		var response any
+		So(json.Unmarshal([]byte(`{
+			"data": {
+				"chapters": [
+					{"title": "Chapter 1", "number": 1, "images": ["1.jpg", "2.jpg"]},
+					{"title": "Chapter 2", "number": 2.5}
+				],
+				"extra": {"b": 2, "a": 1}
+			}
+		}`), &response), ShouldBeNil)
+
+		Convey("Then the path should find values by keys and indexes", func() {
+			So(JSONPath(response, "data.chapters.1.title"), ShouldResemble, []any{"Chapter 2"})
+			So(JSONPath(response, "data.missing.title"), ShouldBeEmpty)
+			So(JSONPath(response, ""), ShouldResemble, []any{response})
+		})
+
+		Convey("Then wildcards should match all the elements in order", func() {
+			So(JSONPath(response, "data.chapters.*.title"), ShouldResemble, []any{"Chapter 1", "Chapter 2"})
+			So(JSONPath(response, "data.extra.*"), ShouldResemble, []any{float64(1), float64(2)})
+		})
+
+		Convey("Then strings should be formatted", func() {
+			So(JSONString("data.chapters.1.number")(response), ShouldEqual, "2.5")
+			So(JSONString("data.chapters.0")(response), ShouldBeEmpty)
+		})
+	})
+}
+
+func TestScraper_JSON(t *testing.T) {
+	Convey("Given a source with HTML search and JSON chapters and pages", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/search":
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = w.Write([]byte(`<html><body><a class="manga" href="/manga/42">Answer</a></body></html>`))
+			case "/api/manga/42/chapters":
+				_, _ = w.Write([]byte(`{"chapters": [{"title": "Chapter 1", "url": "/chapter/7", "volume": "Vol. 1"}]}`))
+			case "/api/chapter/7":
+				_, _ = w.Write([]byte(`{"images": ["/images/1.png", "/images/2.png"]}`))
+			case "/api/manga/broken/chapters":
+				_, _ = w.Write([]byte(`not json`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		id := func(address string) string {
+			return address[strings.LastIndex(address, "/")+1:]
+		}
+
+		scraper := New(&Configuration{
+			Name:        "test",
+			Parallelism: 1,
+			BaseURL:     server.URL,
+			GenerateSearchURL: func(query string) string {
+				return server.URL + "/search?q=" + query
+			},
+			MangaExtractor: &Extractor{
+				Selector: "a.manga",
+				Name:     func(s *goquery.Selection) string { return s.Text() },
+				URL:      func(s *goquery.Selection) string { return s.AttrOr("href", "") },
+				Cover:    func(*goquery.Selection) string { return "" },
+			},
+			ChapterJSONExtractor: &JSONExtractor{
+				Path: "chapters",
+				Endpoint: func(address string) string {
+					return server.URL + "/api/manga/" + id(address) + "/chapters"
+				},
+				Name:   JSONString("title"),
+				URL:    JSONString("url"),
+				Volume: JSONString("volume"),
+			},
+			PageJSONExtractor: &JSONExtractor{
+				Path: "images",
+				Endpoint: func(address string) string {
+					return server.URL + "/api/chapter/" + id(address)
+				},
+				URL: JSONString(""),
+			},
+		})
+
+		Convey("When scraping all the stages", func() {
+			mangas, err := scraper.Search("answer")
+			So(err, ShouldBeNil)
+			So(mangas, ShouldHaveLength, 1)
+
+			chapters, err := scraper.ChaptersOf(mangas[0])
+			So(err, ShouldBeNil)
+
+			pages, err := scraper.PagesOf(chapters[0])
+			So(err, ShouldBeNil)
+
+			Convey("Then JSON stages should follow the HTML one", func() {
+				So(chapters, ShouldHaveLength, 1)
+				So(chapters[0].URL, ShouldEqual, server.URL+"/chapter/7")
+				So(chapters[0].Volume, ShouldEqual, "Vol. 1")
+				So(chapters[0].Number, ShouldEqual, "1")
+
+				So(pages, ShouldHaveLength, 2)
+				So(pages[1].URL, ShouldEqual, server.URL+"/images/2.png")
+				So(pages[1].Extension, ShouldEqual, ".png")
+			})
+		})
+
+		Convey("When the response is not JSON", func() {
+			mangas, err := scraper.Search("answer")
+			So(err, ShouldBeNil)
+			mangas[0].URL = server.URL + "/manga/broken"
+
+			_, err = scraper.ChaptersOf(mangas[0])
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -1,44 +1,96 @@
 package generic
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly/v2"
-	"github.com/preetbiswas12/Kage/constant"
+	"github.com/preetbiswas12/Kage/network"
 	"github.com/preetbiswas12/Kage/source"
-	"github.com/preetbiswas12/Kage/where"
 )
 
 // New generates a new scraper with given configuration
 func New(conf *Configuration) source.Source {
 	s := Scraper{
-		mangas:   make(map[string][]*source.Manga),
-		chapters: make(map[string][]*source.Chapter),
-		pages:    make(map[string][]*source.Page),
-		config:   conf,
+		mangas:    make(map[string][]*source.Manga),
+		nextPages: make(map[string]string),
+		chapters:  make(map[string][]*source.Chapter),
+		pages:     make(map[string][]*source.Page),
+		config:    conf,
 	}
 
 	collectorOptions := []colly.CollectorOption{
 		colly.AllowURLRevisit(),
 		colly.Async(true),
-		colly.CacheDir(where.Cache()),
 	}
 
 	baseCollector := colly.NewCollector(collectorOptions...)
 	baseCollector.SetRequestTimeout(20 * time.Second)
 
-	mangasCollector := baseCollector.Clone()
+	// limits are stored in the http backend which is shared between clones
+	_ = baseCollector.Limit(&colly.LimitRule{
+		Parallelism: int(s.config.Parallelism),
+		RandomDelay: s.config.Delay,
+		DomainGlob:  "*",
+	})
+
+	baseCollector.SetCookieJar(network.Jar)
+
+	// responses are cached with the lifetimes of the stages, see cacheStage
+	baseCollector.WithTransport(newCacheTransport(conf.Name, network.Transport))
+
+	s.collector = baseCollector
+
+	return &s
+}
+
+// clone the base collector so that its requests are bound to the given context
+// and cached for the lifetime of the given stage.
+func (s *Scraper) clone(ctx context.Context, stage cacheStage) *colly.Collector {
+	collector := s.collector.Clone()
+	collector.Context = network.WithSource(withStage(ctx, stage), s.config.Name)
+	return collector
+}
+
+// mangasCollector creates a collector that finds mangas on the search page
+func (s *Scraper) mangasCollector(ctx context.Context) *colly.Collector {
+	mangasCollector := s.clone(ctx, stageSearch)
 	mangasCollector.OnRequest(func(r *colly.Request) {
 		r.Headers.Set("Referer", "https://google.com")
 		r.Headers.Set("accept-language", "en-US")
-		r.Headers.Set("Accept", "text/html")
+		r.Headers.Set("Accept", accept(s.config.MangaJSONExtractor))
 		// Don't manually set Host header - let colly/http handle it
-		r.Headers.Set("User-Agent", constant.UserAgent)
+		r.Headers.Set("User-Agent", network.UserAgent())
 	})
 
+	if extractor := s.config.MangaJSONExtractor; extractor != nil {
+		onJSON(mangasCollector, func(r *colly.Response, response any) {
+			path := r.Request.URL.String()
+			items := extractor.items(response)
+			s.mangas[path] = make([]*source.Manga, len(items))
+
+			for i, item := range items {
+				cover := jsonExtract(extractor.Cover, item)
+				if cover != "" {
+					cover = r.Request.AbsoluteURL(cover)
+				}
+
+				s.mangas[path][i] = s.manga(r.Request, i, jsonExtract(extractor.Name, item), jsonExtract(extractor.URL, item), cover)
+			}
+
+			if extractor.Next != nil {
+				if link := r.Request.AbsoluteURL(extractor.Next(response)); link != "" && link != path {
+					s.nextPages[path] = link
+				}
+			}
+		})
+
+		return mangasCollector
+	}
+
 	// Get mangas
 	mangasCollector.OnHTML("html", func(e *colly.HTMLElement) {
 		elements := e.DOM.Find(s.config.MangaExtractor.Selector)
@@ -46,75 +98,173 @@ func New(conf *Configuration) source.Source {
 		s.mangas[path] = make([]*source.Manga, elements.Length())
 
 		elements.Each(func(i int, selection *goquery.Selection) {
-			link := s.config.MangaExtractor.URL(selection)
-			url := e.Request.AbsoluteURL(link)
-			manga := source.Manga{
-				Name:     s.config.MangaExtractor.Name(selection),
-				URL:      url,
-				Index:    uint16(e.Index),
-				Chapters: make([]*source.Chapter, 0),
-				ID:       filepath.Base(url),
-				Source:   &s,
-			}
-			manga.Metadata.Cover.ExtraLarge = s.config.MangaExtractor.Cover(selection)
-
-			s.mangas[path][i] = &manga
+			s.mangas[path][i] = s.manga(
+				e.Request,
+				e.Index,
+				s.config.MangaExtractor.Name(selection),
+				s.config.MangaExtractor.URL(selection),
+				s.config.MangaExtractor.Cover(selection),
+			)
 		})
-	})
 
-	_ = mangasCollector.Limit(&colly.LimitRule{
-		Parallelism: int(s.config.Parallelism),
-		RandomDelay: s.config.Delay,
-		DomainGlob:  "*",
+		if s.config.MangaNextPageExtractor != nil {
+			next := e.DOM.Find(s.config.MangaNextPageExtractor.Selector).First()
+			if next.Length() > 0 {
+				// ignore links that point to the same page, e.g. disabled "next" buttons
+				if link := e.Request.AbsoluteURL(s.config.MangaNextPageExtractor.URL(next)); link != "" && link != path {
+					s.nextPages[path] = link
+				}
+			}
+		}
 	})
 
-	chaptersCollector := baseCollector.Clone()
+	return mangasCollector
+}
+
+// manga creates the manga found on the page of the request
+func (s *Scraper) manga(r *colly.Request, index int, name, link, cover string) *source.Manga {
+	url := r.AbsoluteURL(link)
+	manga := source.Manga{
+		Name:     name,
+		URL:      url,
+		Index:    uint16(index),
+		Chapters: make([]*source.Chapter, 0),
+		ID:       filepath.Base(url),
+		Source:   s,
+	}
+	manga.Metadata.Cover.ExtraLarge = cover
+
+	return &manga
+}
+
+// chaptersCollector creates a collector that finds chapters on the manga page
+func (s *Scraper) chaptersCollector(ctx context.Context) *colly.Collector {
+	chaptersCollector := s.clone(ctx, stageChapters)
 	chaptersCollector.OnRequest(func(r *colly.Request) {
 		r.Headers.Set("Referer", r.Ctx.GetAny("manga").(*source.Manga).URL)
 		r.Headers.Set("accept-language", "en-US")
-		r.Headers.Set("Accept", "text/html")
+		r.Headers.Set("Accept", accept(s.config.ChapterJSONExtractor))
 		// Don't manually set Host header - let colly/http handle it
-		r.Headers.Set("User-Agent", constant.UserAgent)
+		r.Headers.Set("User-Agent", network.UserAgent())
 	})
 
+	if extractor := s.config.ChapterJSONExtractor; extractor != nil {
+		onJSON(chaptersCollector, func(r *colly.Response, response any) {
+			manga := r.Ctx.GetAny("manga").(*source.Manga)
+			chapters, _ := r.Ctx.GetAny("chapters").([]*source.Chapter)
+			offset := len(chapters)
+
+			for i, item := range extractor.items(response) {
+				chapters = append(chapters, s.chapter(
+					r.Request,
+					manga,
+					offset+i,
+					jsonExtract(extractor.Name, item),
+					jsonExtract(extractor.URL, item),
+					jsonExtract(extractor.Volume, item),
+				))
+			}
+			r.Ctx.Put("chapters", chapters)
+
+			if extractor.Next != nil {
+				s.nextChaptersPage(r.Request, extractor.Next(response))
+			}
+		})
+
+		return chaptersCollector
+	}
+
 	// Get chapters
 	chaptersCollector.OnHTML("html", func(e *colly.HTMLElement) {
-		elements := e.DOM.Find(s.config.ChapterExtractor.Selector)
-		path := e.Request.AbsoluteURL(e.Request.URL.Path)
-		s.chapters[path] = make([]*source.Chapter, elements.Length())
 		manga := e.Request.Ctx.GetAny("manga").(*source.Manga)
+		chapters, _ := e.Request.Ctx.GetAny("chapters").([]*source.Chapter)
+		offset := len(chapters)
 
-		elements.Each(func(i int, selection *goquery.Selection) {
-			link := s.config.ChapterExtractor.URL(selection)
-			url := e.Request.AbsoluteURL(link)
-
-			chapter := source.Chapter{
-				Name:   s.config.ChapterExtractor.Name(selection),
-				URL:    url,
-				Index:  uint16(e.Index),
-				Pages:  make([]*source.Page, 0),
-				ID:     filepath.Base(url),
-				Manga:  manga,
-				Volume: s.config.ChapterExtractor.Volume(selection),
-			}
-			s.chapters[path][i] = &chapter
+		e.DOM.Find(s.config.ChapterExtractor.Selector).Each(func(i int, selection *goquery.Selection) {
+			chapters = append(chapters, s.chapter(
+				e.Request,
+				manga,
+				offset+i,
+				s.config.ChapterExtractor.Name(selection),
+				s.config.ChapterExtractor.URL(selection),
+				s.config.ChapterExtractor.Volume(selection),
+			))
 		})
-		manga.Chapters = s.chapters[path]
-	})
-	_ = chaptersCollector.Limit(&colly.LimitRule{
-		Parallelism: int(s.config.Parallelism),
-		RandomDelay: s.config.Delay,
-		DomainGlob:  "*",
+		e.Request.Ctx.Put("chapters", chapters)
+
+		if s.config.ChapterNextPageExtractor != nil {
+			next := e.DOM.Find(s.config.ChapterNextPageExtractor.Selector).First()
+			if next.Length() > 0 {
+				s.nextChaptersPage(e.Request, s.config.ChapterNextPageExtractor.URL(next))
+			}
+		}
 	})
 
-	pagesCollector := baseCollector.Clone()
+	return chaptersCollector
+}
+
+// nextChaptersPage visits the next page of the chapters list, unless it was visited already or the limit of pages is reached.
+// Chapters of the next page are appended to the ones found so far, see ChaptersOfContext
+func (s *Scraper) nextChaptersPage(r *colly.Request, link string) {
+	visited, _ := r.Ctx.GetAny("chapterPages").(map[string]bool)
+	if visited == nil {
+		visited = make(map[string]bool)
+		r.Ctx.Put("chapterPages", visited)
+	}
+
+	visited[r.URL.String()] = true
+
+	// ignore links to the visited pages, e.g. disabled "next" buttons or pagination that cycles back
+	if link = r.AbsoluteURL(link); link == "" || visited[link] || len(visited) >= s.config.maxChapterPages() {
+		return
+	}
+
+	visited[link] = true
+	if err := r.Visit(link); err != nil {
+		r.Ctx.Put("error", err)
+	}
+}
+
+// chapter creates the chapter of the manga found on the page of the request
+func (s *Scraper) chapter(r *colly.Request, manga *source.Manga, index int, name, link, volume string) *source.Chapter {
+	url := r.AbsoluteURL(link)
+	return &source.Chapter{
+		Name:   name,
+		Number: source.ParseChapterNumber(name),
+		URL:    url,
+		Index:  uint16(index + 1),
+		Pages:  make([]*source.Page, 0),
+		ID:     filepath.Base(url),
+		Manga:  manga,
+		Volume: volume,
+	}
+}
+
+// pagesCollector creates a collector that finds pages on the chapter page
+func (s *Scraper) pagesCollector(ctx context.Context) *colly.Collector {
+	pagesCollector := s.clone(ctx, stagePages)
 	pagesCollector.OnRequest(func(r *colly.Request) {
 		r.Headers.Set("Referer", r.Ctx.GetAny("chapter").(*source.Chapter).URL)
 		r.Headers.Set("accept-language", "en-US")
-		r.Headers.Set("Accept", "text/html")
-		r.Headers.Set("User-Agent", constant.UserAgent)
+		r.Headers.Set("Accept", accept(s.config.PageJSONExtractor))
+		r.Headers.Set("User-Agent", network.UserAgent())
 	})
 
+	if extractor := s.config.PageJSONExtractor; extractor != nil {
+		onJSON(pagesCollector, func(r *colly.Response, response any) {
+			chapter := r.Ctx.GetAny("chapter").(*source.Chapter)
+			items := extractor.items(response)
+			s.pages[chapter.URL] = make([]*source.Page, len(items))
+
+			for i, item := range items {
+				s.pages[chapter.URL][i] = page(chapter, i, r.Request.AbsoluteURL(jsonExtract(extractor.URL, item)))
+			}
+			chapter.Pages = s.pages[chapter.URL]
+		})
+
+		return pagesCollector
+	}
+
 	// Get pages
 	pagesCollector.OnHTML("html", func(e *colly.HTMLElement) {
 		elements := e.DOM.Find(s.config.PageExtractor.Selector)
@@ -123,30 +273,33 @@ func New(conf *Configuration) source.Source {
 		chapter := e.Request.Ctx.GetAny("chapter").(*source.Chapter)
 
 		elements.Each(func(i int, selection *goquery.Selection) {
-			link := s.config.PageExtractor.URL(selection)
-			ext := filepath.Ext(link)
-			// remove some query params from the extension
-			ext = strings.Split(ext, "?")[0]
-
-			page := source.Page{
-				URL:       link,
-				Index:     uint16(i),
-				Chapter:   chapter,
-				Extension: ext,
-			}
-			s.pages[path][i] = &page
+			s.pages[path][i] = page(chapter, i, s.config.PageExtractor.URL(selection))
 		})
 		chapter.Pages = s.pages[path]
 	})
-	_ = pagesCollector.Limit(&colly.LimitRule{
-		Parallelism: int(s.config.Parallelism),
-		RandomDelay: s.config.Delay,
-		DomainGlob:  "*",
-	})
 
-	s.mangasCollector = mangasCollector
-	s.chaptersCollector = chaptersCollector
-	s.pagesCollector = pagesCollector
+	return pagesCollector
+}
 
-	return &s
+// page creates the page of the chapter with the given image link
+func page(chapter *source.Chapter, index int, link string) *source.Page {
+	ext := filepath.Ext(link)
+	// remove some query params from the extension
+	ext = strings.Split(ext, "?")[0]
+
+	return &source.Page{
+		URL:       link,
+		Index:     uint16(index),
+		Chapter:   chapter,
+		Extension: ext,
+	}
+}
+
+// accept is the Accept header of the stage requests, depending on whether the stage reads JSON
+func accept(extractor *JSONExtractor) string {
+	if extractor != nil {
+		return "application/json"
+	}
+
+	return "text/html"
 }
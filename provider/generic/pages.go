@@ -1,6 +1,7 @@
 package generic
 
 import (
+	"context"
 	"github.com/gocolly/colly/v2"
 	"github.com/preetbiswas12/Kage/source"
 	"net/http"
@@ -8,19 +9,39 @@ import (
 
 // PagesOf given source.Chapter
 func (s *Scraper) PagesOf(chapter *source.Chapter) ([]*source.Page, error) {
+	return s.PagesOfContext(context.Background(), chapter)
+}
+
+// PagesOfContext returns pages of given source.Chapter until the context is done
+func (s *Scraper) PagesOfContext(ctx context.Context, chapter *source.Chapter) ([]*source.Page, error) {
 	if pages, ok := s.pages[chapter.URL]; ok {
 		return pages, nil
 	}
 
-	ctx := colly.NewContext()
-	ctx.Put("chapter", chapter)
-	err := s.pagesCollector.Request(http.MethodGet, chapter.URL, nil, ctx, nil)
+	collyCtx := colly.NewContext()
+	collyCtx.Put("chapter", chapter)
+
+	address := chapter.URL
+	if s.config.PageJSONExtractor != nil {
+		address = s.config.PageJSONExtractor.endpoint(address)
+	}
+
+	collector := s.pagesCollector(ctx)
+	err := collector.Request(http.MethodGet, address, nil, collyCtx, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	s.pagesCollector.Wait()
+	collector.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = responseError(collyCtx); err != nil {
+		return nil, err
+	}
 
 	return s.pages[chapter.URL], nil
 }
@@ -7,13 +7,15 @@ import (
 
 // Scraper is a generic scraper downloads html pages and parses them
 type Scraper struct {
-	mangasCollector   *colly.Collector
-	chaptersCollector *colly.Collector
-	pagesCollector    *colly.Collector
+	// collector is the base collector that stage collectors are cloned from.
+	// Clones share its http backend, so limits and cache are shared as well.
+	collector *colly.Collector
 
-	mangas   map[string][]*source.Manga
-	chapters map[string][]*source.Chapter
-	pages    map[string][]*source.Page
+	mangas map[string][]*source.Manga
+	// nextPages maps search page URL to the URL of the following page
+	nextPages map[string]string
+	chapters  map[string][]*source.Chapter
+	pages     map[string][]*source.Page
 
 	config *Configuration
 }
@@ -1,23 +1,62 @@
 package generic
 
 import (
+	"context"
+	"github.com/gocolly/colly/v2"
 	"github.com/preetbiswas12/Kage/source"
+	"net/http"
 )
 
 // Search for mangas by given title
 func (s *Scraper) Search(query string) ([]*source.Manga, error) {
-	address := s.config.GenerateSearchURL(query)
+	return s.SearchContext(context.Background(), query)
+}
+
+// SearchContext searches for mangas by given title until the context is done
+func (s *Scraper) SearchContext(ctx context.Context, query string) ([]*source.Manga, error) {
+	page, err := s.SearchPage(ctx, query, "")
+	if err != nil {
+		return nil, err
+	}
 
-	if urls, ok := s.mangas[address]; ok {
-		return urls, nil
+	return page.Mangas, nil
+}
+
+// SearchPage searches for mangas on the page given by the token.
+// Token is the URL of the page, empty token stands for the first one.
+func (s *Scraper) SearchPage(ctx context.Context, query, token string) (*source.SearchPage, error) {
+	address := token
+	if address == "" {
+		address = s.config.GenerateSearchURL(query)
 	}
 
-	err := s.mangasCollector.Visit(address)
+	return s.mangasPage(ctx, address)
+}
+
+// mangasPage finds mangas on the page with the given address
+func (s *Scraper) mangasPage(ctx context.Context, address string) (*source.SearchPage, error) {
+	if mangas, ok := s.mangas[address]; ok {
+		return &source.SearchPage{Mangas: mangas, Next: s.nextPages[address]}, nil
+	}
+
+	collyCtx := colly.NewContext()
+
+	collector := s.mangasCollector(ctx)
+	err := collector.Request(http.MethodGet, address, nil, collyCtx, nil)
 
 	if err != nil {
 		return nil, err
 	}
 
-	s.mangasCollector.Wait()
-	return s.mangas[address], nil
+	collector.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = responseError(collyCtx); err != nil {
+		return nil, err
+	}
+
+	return &source.SearchPage{Mangas: s.mangas[address], Next: s.nextPages[address]}, nil
 }
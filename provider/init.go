@@ -1,14 +1,20 @@
 package provider
 
 import (
+	"github.com/preetbiswas12/Kage/provider/declarative"
 	"github.com/preetbiswas12/Kage/provider/generic"
+	"github.com/preetbiswas12/Kage/provider/local"
 	"github.com/preetbiswas12/Kage/provider/mangadex"
 	"github.com/preetbiswas12/Kage/provider/mangapill"
+	"github.com/preetbiswas12/Kage/provider/opds"
 	"github.com/preetbiswas12/Kage/source"
 )
 
 const CustomProviderExtension = ".lua"
 
+// CustomProviderExtensions are extensions of all the custom sources, both Lua and declarative
+var CustomProviderExtensions = append([]string{CustomProviderExtension}, declarative.Extensions...)
+
 var builtinProviders = []*Provider{
 	{
 		ID:   mangadex.ID,
@@ -17,6 +23,26 @@ var builtinProviders = []*Provider{
 			return mangadex.New(), nil
 		},
 	},
+	{
+		ID:   local.ID,
+		Name: local.Name,
+		CreateSource: func() (source.Source, error) {
+			return local.New(), nil
+		},
+	},
+}
+
+var opdsProvider = &Provider{
+	ID:   opds.ID,
+	Name: opds.Name,
+	CreateSource: func() (source.Source, error) {
+		src, err := opds.New()
+		if err != nil {
+			return nil, err
+		}
+
+		return src, nil
+	},
 }
 
 func init() {
@@ -0,0 +1,218 @@
+package local
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/samber/lo"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const comicInfoFile = "ComicInfo.xml"
+
+var (
+	// archiveExtensions are extensions of the chapters stored as archives
+	archiveExtensions = []string{".cbz", ".zip"}
+	// imageExtensions are extensions of the page images
+	imageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp"}
+)
+
+func isArchive(name string) bool {
+	return lo.Contains(archiveExtensions, strings.ToLower(filepath.Ext(name)))
+}
+
+func isImage(name string) bool {
+	return lo.Contains(imageExtensions, strings.ToLower(filepath.Ext(name)))
+}
+
+func (l *Local) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
+	chapters, err := l.chaptersIn(manga, manga.URL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	source.SortChapters(chapters)
+	for i, chapter := range chapters {
+		chapter.Index = uint16(i + 1)
+	}
+
+	manga.Chapters = chapters
+	return chapters, nil
+}
+
+// ChapterPath returns the path of the chapter archive or directory
+func (*Local) ChapterPath(chapter *source.Chapter) string {
+	return chapter.URL
+}
+
+// chaptersIn returns the chapters in the directory.
+// Directories without images are treated as volumes
+func (l *Local) chaptersIn(manga *source.Manga, dir, volume string) ([]*source.Chapter, error) {
+	entries, err := filesystem.Api().ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var chapters []*source.Chapter
+	for _, entry := range entries {
+		if isHidden(entry) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+
+		switch {
+		case isArchive(entry.Name()):
+			chapters = append(chapters, newChapter(manga, path, volume, readArchiveComicInfo(path)))
+		case entry.IsDir():
+			images, err := imagesIn(path)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(images) > 0 {
+				chapters = append(chapters, newChapter(manga, path, volume, readDirComicInfo(path)))
+				continue
+			}
+
+			// nested volumes are not created by the downloader
+			if volume != "" {
+				continue
+			}
+
+			inVolume, err := l.chaptersIn(manga, path, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+
+			chapters = append(chapters, inVolume...)
+		}
+	}
+
+	return chapters, nil
+}
+
+// newChapter creates the chapter stored at the path.
+// ComicInfo.xml, if present, provides the chapter details
+func newChapter(manga *source.Manga, path, volume string, info *source.ComicInfo) *source.Chapter {
+	chapter := &source.Chapter{
+		Name:   util.FileStem(path),
+		URL:    path,
+		ID:     strings.TrimPrefix(path, manga.URL+string(filepath.Separator)),
+		Volume: volume,
+		Manga:  manga,
+	}
+
+	if info != nil {
+		if info.Title != "" {
+			chapter.Name = info.Title
+		}
+
+		chapter.Number = source.NormalizeChapterNumber(info.Number)
+		chapter.Language = info.LanguageISO
+		chapter.PagesCount = info.PageCount
+
+		if info.Translator != "" {
+			chapter.Groups = strings.Split(info.Translator, ",")
+		}
+
+		if info.Year != 0 {
+			chapter.PublishedAt = time.Date(info.Year, time.Month(util.Max(info.Month, 1)), util.Max(info.Day, 1), 0, 0, 0, 0, time.UTC)
+		}
+	}
+
+	if chapter.Number == "" {
+		chapter.Number = source.ParseChapterNumber(chapter.Name)
+	}
+
+	return chapter
+}
+
+// imagesIn returns names of the images in the directory sorted by name
+func imagesIn(dir string) ([]string, error) {
+	entries, err := filesystem.Api().ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, entry := range entries {
+		if !entry.IsDir() && !isHidden(entry) && isImage(entry.Name()) {
+			images = append(images, entry.Name())
+		}
+	}
+
+	return images, nil
+}
+
+// openArchive opens the chapter archive. The returned file must be closed
+func openArchive(path string) (*zip.Reader, io.Closer, error) {
+	file, err := filesystem.Api().Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	reader, err := zip.NewReader(file, stat.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	return reader, file, nil
+}
+
+// readArchiveComicInfo reads ComicInfo.xml of the chapter archive.
+// Returns nil if the archive has none
+func readArchiveComicInfo(path string) *source.ComicInfo {
+	reader, closer, err := openArchive(path)
+	if err != nil {
+		return nil
+	}
+
+	defer util.Ignore(closer.Close)
+
+	file, err := reader.Open(comicInfoFile)
+	if err != nil {
+		return nil
+	}
+
+	defer util.Ignore(file.Close)
+
+	contents, err := io.ReadAll(file)
+	if err != nil {
+		return nil
+	}
+
+	return parseComicInfo(contents)
+}
+
+// readDirComicInfo reads ComicInfo.xml of the chapter directory.
+// Returns nil if the directory has none
+func readDirComicInfo(path string) *source.ComicInfo {
+	contents, err := filesystem.Api().ReadFile(filepath.Join(path, comicInfoFile))
+	if err != nil {
+		return nil
+	}
+
+	return parseComicInfo(contents)
+}
+
+func parseComicInfo(contents []byte) *source.ComicInfo {
+	var info source.ComicInfo
+	if err := xml.Unmarshal(contents, &info); err != nil {
+		return nil
+	}
+
+	return &info
+}
@@ -0,0 +1,63 @@
+package local
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/source"
+	"strings"
+)
+
+// MangaDetails fills the metadata from series.json of the manga
+// and the first ComicInfo.xml found among its chapters
+func (l *Local) MangaDetails(_ context.Context, manga *source.Manga) error {
+	if series, err := readSeriesJSON(manga.URL); err == nil {
+		manga.Metadata.Summary = series.Metadata.DescriptionText
+		manga.Metadata.StartDate.Year = series.Metadata.Year
+		manga.Metadata.Cover.ExtraLarge = series.Metadata.ComicImage
+		manga.Metadata.Chapters = series.Metadata.TotalIssues
+
+		switch series.Metadata.Status {
+		case "Ended":
+			manga.Metadata.Status = source.StatusFinished
+		case "Continuing":
+			manga.Metadata.Status = source.StatusReleasing
+		}
+	}
+
+	var info *source.ComicInfo
+	for _, chapter := range manga.Chapters {
+		if isArchive(chapter.URL) {
+			info = readArchiveComicInfo(chapter.URL)
+		} else {
+			info = readDirComicInfo(chapter.URL)
+		}
+
+		if info != nil {
+			break
+		}
+	}
+
+	if info == nil {
+		return nil
+	}
+
+	if manga.Metadata.Summary == "" {
+		manga.Metadata.Summary = info.Summary
+	}
+
+	manga.Metadata.Genres = split(info.Genre)
+	manga.Metadata.Tags = split(info.Tags)
+	manga.Metadata.Characters = split(info.Characters)
+	manga.Metadata.Staff.Story = split(info.Writer)
+	manga.Metadata.Staff.Art = split(info.Penciller)
+	manga.Metadata.Staff.Lettering = split(info.Letterer)
+	return nil
+}
+
+// split splits the comma separated ComicInfo.xml values
+func split(values string) []string {
+	if values == "" {
+		return nil
+	}
+
+	return strings.Split(values, ",")
+}
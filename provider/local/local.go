@@ -0,0 +1,30 @@
+package local
+
+import (
+	"github.com/preetbiswas12/Kage/where"
+)
+
+const (
+	Name = "Local"
+	ID   = Name + " built-in"
+)
+
+// Local is a source of the mangas in the downloads directory.
+// Each manga is a directory with chapters as CBZ or ZIP archives or as plain directories of images.
+// Volume directories are supported as well
+type Local struct {
+	root string
+}
+
+func (*Local) Name() string {
+	return Name
+}
+
+func (*Local) ID() string {
+	return ID
+}
+
+// New creates the source of the mangas in the downloads directory
+func New() *Local {
+	return &Local{root: where.Downloads()}
+}
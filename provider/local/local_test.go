@@ -0,0 +1,90 @@
+package local
+
+import (
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	Convey("Given a local library", t, func() {
+		filesystem.SetMemMapFs()
+		root := "/downloads"
+		manga := filepath.Join(root, "One Piece")
+		write := func(path string, contents []byte) {
+			So(filesystem.Api().MkdirAll(filepath.Dir(path), 0755), ShouldBeNil)
+			So(filesystem.Api().WriteFile(path, contents, 0644), ShouldBeNil)
+		}
+
+		write(filepath.Join(manga, "series.json"), []byte(`{"metadata":{"name":"One Piece: Original","status":"Continuing"}}`))
+		write(filepath.Join(manga, "[0002] Chapter 2.cbz"), lo.Must(util.Zip(map[string]string{
+			"2.jpg":         "second",
+			"1.jpg":         "first",
+			"ComicInfo.xml": `<ComicInfo><Title>Romance Dawn 2</Title><Number>2</Number><Genre>Action,Comedy</Genre></ComicInfo>`,
+		})))
+		write(filepath.Join(manga, "Vol.1", "Chapter 1", "1.png"), []byte("png"))
+		write(filepath.Join(root, "Berserk", "Chapter 10.zip"), lo.Must(util.Zip(map[string]string{"1.jpg": "page"})))
+
+		local := &Local{root: root}
+
+		Convey("When searching", func() {
+			mangas, err := local.Search("piece")
+
+			Convey("The matching manga should be found with its original name", func() {
+				So(err, ShouldBeNil)
+				So(mangas, ShouldHaveLength, 1)
+				So(mangas[0].Name, ShouldEqual, "One Piece: Original")
+				So(mangas[0].Source, ShouldEqual, local)
+
+				Convey("And its chapters should be listed from archives and directories", func() {
+					chapters, err := local.ChaptersOf(mangas[0])
+					So(err, ShouldBeNil)
+					So(chapters, ShouldHaveLength, 2)
+					So(chapters[0].Name, ShouldEqual, "Chapter 1")
+					So(chapters[0].Volume, ShouldEqual, "Vol.1")
+					So(chapters[1].Name, ShouldEqual, "Romance Dawn 2")
+					So(chapters[1].Number, ShouldEqual, "2")
+
+					Convey("And the pages of the archive should be read", func() {
+						pages, err := local.PagesOf(chapters[1])
+						So(err, ShouldBeNil)
+						So(pages, ShouldHaveLength, 2)
+
+						So(pages[0].Download(), ShouldBeNil)
+						So(pages[0].Contents.String(), ShouldEqual, "first")
+						So(pages[0].Size, ShouldEqual, 5)
+					})
+
+					Convey("And the pages of the directory should be read", func() {
+						pages, err := local.PagesOf(chapters[0])
+						So(err, ShouldBeNil)
+						So(pages, ShouldHaveLength, 1)
+						So(pages[0].Extension, ShouldEqual, ".png")
+
+						So(pages[0].Download(), ShouldBeNil)
+						So(pages[0].Contents.String(), ShouldEqual, "png")
+					})
+
+					Convey("And the details should be read", func() {
+						So(local.MangaDetails(nil, mangas[0]), ShouldBeNil)
+						So(mangas[0].Metadata.Status, ShouldEqual, source.StatusReleasing)
+						So(mangas[0].Metadata.Genres, ShouldResemble, []string{"Action", "Comedy"})
+					})
+				})
+			})
+		})
+
+		Convey("When browsing", func() {
+			page, err := local.BrowsePage(nil, source.BrowseLatest, "")
+
+			Convey("All the mangas should be listed", func() {
+				So(err, ShouldBeNil)
+				So(page.Mangas, ShouldHaveLength, 2)
+			})
+		})
+	})
+}
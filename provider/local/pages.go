@@ -0,0 +1,138 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/preetbiswas12/Kage/util"
+	"io"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func (l *Local) PagesOf(chapter *source.Chapter) ([]*source.Page, error) {
+	var (
+		names []string
+		err   error
+	)
+
+	archive := isArchive(chapter.URL)
+	if archive {
+		names, err = archiveImages(chapter.URL)
+	} else {
+		names, err = imagesIn(chapter.URL)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no pages found in %s", chapter.URL)
+	}
+
+	sort.Strings(names)
+
+	pages := make([]*source.Page, len(names))
+	for i, name := range names {
+		// pages of archives are addressed by the fragment
+		u := fileURL(filepath.Join(chapter.URL, name), "")
+		if archive {
+			u = fileURL(chapter.URL, name)
+		}
+
+		pages[i] = &source.Page{
+			URL:       u,
+			Index:     uint16(i),
+			Chapter:   chapter,
+			Extension: filepath.Ext(name),
+		}
+	}
+
+	chapter.Pages = pages
+	return pages, nil
+}
+
+// DownloadPage reads the page from the chapter directory or archive
+func (l *Local) DownloadPage(_ context.Context, page *source.Page) error {
+	u, err := url.Parse(page.URL)
+	if err != nil {
+		return err
+	}
+
+	path := u.Path
+	// windows paths are given as /C:/path
+	if filepath.VolumeName(strings.TrimPrefix(path, "/")) != "" {
+		path = strings.TrimPrefix(path, "/")
+	}
+
+	path = filepath.FromSlash(path)
+
+	var contents []byte
+	if u.Fragment != "" {
+		contents, err = readArchiveFile(path, u.Fragment)
+	} else {
+		contents, err = filesystem.Api().ReadFile(path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read page #%d: %w", page.Index, err)
+	}
+
+	page.Contents = bytes.NewBuffer(contents)
+	page.Size = uint64(len(contents))
+	return nil
+}
+
+// fileURL returns the file URL of the absolute path
+func fileURL(path, fragment string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	u := url.URL{Scheme: "file", Path: path, Fragment: fragment}
+	return u.String()
+}
+
+// archiveImages returns names of the images in the archive
+func archiveImages(path string) ([]string, error) {
+	reader, closer, err := openArchive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer util.Ignore(closer.Close)
+
+	var images []string
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() && isImage(file.Name) {
+			images = append(images, file.Name)
+		}
+	}
+
+	return images, nil
+}
+
+// readArchiveFile reads the file from the archive
+func readArchiveFile(path, name string) ([]byte, error) {
+	reader, closer, err := openArchive(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer util.Ignore(closer.Close)
+
+	file, err := reader.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer util.Ignore(file.Close)
+
+	return io.ReadAll(file)
+}
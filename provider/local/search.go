@@ -0,0 +1,118 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const seriesJSONFile = "series.json"
+
+func (l *Local) Search(query string) ([]*source.Manga, error) {
+	mangas, err := l.mangas()
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	mangas = lo.Filter(mangas, func(manga *source.Manga, _ int) bool {
+		return strings.Contains(strings.ToLower(manga.Name), query)
+	})
+
+	for i, manga := range mangas {
+		manga.Index = uint16(i)
+	}
+
+	return mangas, nil
+}
+
+func (l *Local) BrowseLists() []source.BrowseList {
+	return []source.BrowseList{source.BrowseLatest}
+}
+
+// BrowsePage lists all the local mangas, the recently modified ones first
+func (l *Local) BrowsePage(_ context.Context, _ source.BrowseList, _ string) (*source.SearchPage, error) {
+	mangas, err := l.mangas()
+	if err != nil {
+		return nil, err
+	}
+
+	modified := make(map[*source.Manga]int64, len(mangas))
+	for _, manga := range mangas {
+		if info, err := filesystem.Api().Stat(manga.URL); err == nil {
+			modified[manga] = info.ModTime().UnixNano()
+		}
+	}
+
+	sort.SliceStable(mangas, func(i, j int) bool {
+		return modified[mangas[i]] > modified[mangas[j]]
+	})
+
+	for i, manga := range mangas {
+		manga.Index = uint16(i)
+	}
+
+	return &source.SearchPage{Mangas: mangas}, nil
+}
+
+// mangas returns all the manga directories sorted by name
+func (l *Local) mangas() ([]*source.Manga, error) {
+	entries, err := filesystem.Api().ReadDir(l.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var mangas []*source.Manga
+	for _, entry := range entries {
+		if !entry.IsDir() || isHidden(entry) {
+			continue
+		}
+
+		path := filepath.Join(l.root, entry.Name())
+		manga := &source.Manga{
+			Name:   entry.Name(),
+			URL:    path,
+			ID:     entry.Name(),
+			Source: l,
+		}
+
+		// directory names are sanitized, series.json keeps the original name
+		if series, err := readSeriesJSON(path); err == nil && series.Metadata.Name != "" {
+			manga.Name = series.Metadata.Name
+		}
+
+		mangas = append(mangas, manga)
+	}
+
+	sort.SliceStable(mangas, func(i, j int) bool {
+		return strings.ToLower(mangas[i].Name) < strings.ToLower(mangas[j].Name)
+	})
+
+	return mangas, nil
+}
+
+// readSeriesJSON reads series.json of the manga directory
+func readSeriesJSON(mangaPath string) (*source.SeriesJSON, error) {
+	contents, err := filesystem.Api().ReadFile(filepath.Join(mangaPath, seriesJSONFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var series source.SeriesJSON
+	if err = json.Unmarshal(contents, &series); err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+// isHidden reports whether the file should not be listed
+func isHidden(info os.FileInfo) bool {
+	return strings.HasPrefix(info.Name(), ".")
+}
@@ -0,0 +1,39 @@
+package mangadex
+
+import (
+	"context"
+	"fmt"
+	"github.com/preetbiswas12/Kage/source"
+	"net/url"
+)
+
+// browseOrders maps browse lists to the ordering params
+var browseOrders = map[source.BrowseList]string{
+	source.BrowseLatest:  "order[latestUploadedChapter]",
+	source.BrowsePopular: "order[followedCount]",
+	source.BrowseRecent:  "order[createdAt]",
+}
+
+func (*Mangadex) BrowseLists() []source.BrowseList {
+	return []source.BrowseList{source.BrowseLatest, source.BrowsePopular, source.BrowseRecent}
+}
+
+// BrowsePage lists mangas starting from the offset given by the token.
+// Lists are not cached since they change frequently.
+func (m *Mangadex) BrowsePage(ctx context.Context, list source.BrowseList, token string) (*source.SearchPage, error) {
+	order, ok := browseOrders[list]
+	if !ok {
+		return nil, fmt.Errorf("unsupported browse list %s", list)
+	}
+
+	offset, err := parseOffset(token)
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	applyFilters(params, nil)
+	params.Set(order, "desc")
+
+	return m.mangaListPage(ctx, params, offset)
+}
@@ -13,12 +13,16 @@ type cacher[T any] struct {
 	internal *gache.Cache[map[string]T]
 }
 
-func newCacher[T any](name string) *cacher[T] {
+// chaptersLifetime is shorter than the one of the other caches, so that the chapter lists
+// are revalidated by the HTTP cache soon after they change
+const chaptersLifetime = 10 * time.Minute
+
+func newCacher[T any](name string, lifetime time.Duration) *cacher[T] {
 	return &cacher[T]{
 		internal: gache.New[map[string]T](
 			&gache.Options{
 				Path:       filepath.Join(where.Cache(), name+".json"),
-				Lifetime:   time.Hour * 24,
+				Lifetime:   lifetime,
 				FileSystem: &filesystem.GacheFs{},
 			},
 		),
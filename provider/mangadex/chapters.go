@@ -1,18 +1,62 @@
 package mangadex
 
 import (
+	"context"
 	"fmt"
 	"github.com/darylhjd/mangodex"
 	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
 	"github.com/spf13/viper"
-	"golang.org/x/exp/slices"
+	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// chapterList is a response of the manga feed endpoint.
+// mangodex.ChapterList lacks the pages count of the chapters
+type chapterList struct {
+	Result string    `json:"result"`
+	Data   []chapter `json:"data"`
+	Total  int       `json:"total"`
+}
+
+func (c *chapterList) GetResult() string {
+	return c.Result
+}
+
+type chapter struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		mangodex.ChapterAttributes
+		Pages int `json:"pages"`
+	} `json:"attributes"`
+	Relationships []mangodex.Relationship `json:"relationships"`
+}
+
+// mangaChapters requests the page of manga chapters
+func (m *Mangadex) mangaChapters(ctx context.Context, id string, params url.Values) (*chapterList, error) {
+	u, _ := url.Parse(mangodex.BaseAPI)
+	u.Path = fmt.Sprintf(mangodex.MangaChaptersPath, id)
+	u.RawQuery = params.Encode()
+
+	var list chapterList
+	err := m.client.RequestAndDecode(withSource(ctx), http.MethodGet, u.String(), nil, &list)
+	return &list, err
+}
+
 func (m *Mangadex) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
-	if cached, ok := m.cache.chapters.Get(manga.URL).Get(); ok {
+	return m.ChaptersOfContext(context.Background(), manga)
+}
+
+func (m *Mangadex) ChaptersOfContext(ctx context.Context, manga *source.Manga) ([]*source.Chapter, error) {
+	// chapters are selected by the languages, the ones of another selection can't be reused
+	languages := mangaLanguages(manga)
+	cacheKey := manga.URL + "|" + strings.Join(languages, ",")
+
+	if cached, ok := m.cache.chapters.Get(cacheKey).Get(); ok {
 		for _, chapter := range cached {
 			chapter.Manga = manga
 		}
@@ -36,16 +80,21 @@ func (m *Mangadex) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
 	params.Add("includes[]", mangodex.ScanlationGroupRel)
 	params.Set("order[chapter]", "asc")
 
+	// let the server filter the languages, unless any language is accepted
+	if !lo.Contains(languages, anyLanguage) {
+		for _, language := range languages {
+			params.Add("translatedLanguage[]", language)
+		}
+	}
+
 	var (
 		chapters   []*source.Chapter
 		currOffset = 0
 	)
 
-	language := viper.GetString(key.MangadexLanguage)
-
 	for {
 		params.Set("offset", strconv.Itoa(currOffset))
-		list, err := m.client.Chapter.GetMangaChapters(manga.ID, params)
+		list, err := m.mangaChapters(ctx, manga.ID, params)
 		if err != nil {
 			return nil, err
 		}
@@ -56,52 +105,74 @@ func (m *Mangadex) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
 				continue
 			}
 
-			// skip chapters that are not in the current language
-			if language != "any" && chapter.Attributes.TranslatedLanguage != language {
-				currOffset += 500
-				continue
+			num := "-"
+			if chapter.Attributes.Chapter != nil {
+				num = *chapter.Attributes.Chapter
 			}
 
-			name := chapter.GetTitle()
+			name := chapter.Attributes.Title
 			if name == "" {
-				name = fmt.Sprintf("Chapter %s", chapter.GetChapterNum())
+				name = fmt.Sprintf("Chapter %s", num)
 			} else {
-				name = fmt.Sprintf("Chapter %s - %s", chapter.GetChapterNum(), name)
+				name = fmt.Sprintf("Chapter %s - %s", num, name)
 			}
 
 			var volume string
 			if chapter.Attributes.Volume != nil {
 				volume = fmt.Sprintf("Vol.%s", *chapter.Attributes.Volume)
 			}
+
+			var number string
+			if chapter.Attributes.Chapter != nil {
+				number = source.NormalizeChapterNumber(*chapter.Attributes.Chapter)
+			}
+
+			// publish date is informational, ignore malformed ones
+			publishedAt, _ := time.Parse(time.RFC3339, chapter.Attributes.PublishAt)
+
+			// index is the position in the list ordered by the server, as it was before the numbers were parsed,
+			// so that the file names and history entries of the downloaded chapters are kept
 			chapters = append(chapters, &source.Chapter{
-				Name:   name,
-				Index:  uint16(i),
-				ID:     chapter.ID,
-				URL:    fmt.Sprintf("https://mangadex.org/chapter/%s", chapter.ID),
-				Manga:  manga,
-				Volume: volume,
+				Name:        name,
+				Number:      number,
+				Index:       uint16(currOffset + i),
+				ID:          chapter.ID,
+				URL:         fmt.Sprintf("https://mangadex.org/chapter/%s", chapter.ID),
+				Manga:       manga,
+				Volume:      volume,
+				Groups:      scanlationGroups(chapter.Relationships),
+				PagesCount:  chapter.Attributes.Pages,
+				Language:    chapter.Attributes.TranslatedLanguage,
+				PublishedAt: publishedAt,
 			})
 		}
 		currOffset += 500
 		if currOffset >= list.Total {
 			break
 		}
-
-		if currOffset >= list.Total {
-			break
-		}
 	}
 
-	slices.SortFunc(chapters, func(a, b *source.Chapter) int {
-		if a.Index < b.Index {
-			return -1
-		} else if a.Index > b.Index {
-			return 1
-		}
-		return 0
-	})
+	chapters = selectLanguages(chapters, languages)
+	source.SortChapters(chapters)
 
 	manga.Chapters = chapters
-	_ = m.cache.chapters.Set(manga.URL, chapters)
+	_ = m.cache.chapters.Set(cacheKey, chapters)
 	return chapters, nil
 }
+
+// scanlationGroups returns names of the groups included in the chapter relationships
+func scanlationGroups(relationships []mangodex.Relationship) []string {
+	var groups []string
+
+	for _, relationship := range relationships {
+		if relationship.Type != mangodex.ScanlationGroupRel {
+			continue
+		}
+
+		if attributes, ok := relationship.Attributes.(*mangodex.ScanlationGroupAttributes); ok && attributes.Name != "" {
+			groups = append(groups, attributes.Name)
+		}
+	}
+
+	return groups
+}
@@ -0,0 +1,123 @@
+package mangadex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/darylhjd/mangodex"
+	"github.com/preetbiswas12/Kage/source"
+	"golang.org/x/exp/slices"
+	"net/url"
+	"strings"
+)
+
+// statuses maps Mangadex statuses to the ones used in the metadata
+var statuses = map[string]string{
+	mangodex.OngoingStatus:   source.StatusReleasing,
+	mangodex.CompletedStatus: source.StatusFinished,
+	mangodex.HiatusStatus:    source.StatusHiatus,
+	mangodex.CancelledStatus: source.StatusCancelled,
+}
+
+// MangaDetails fills authors, artists, tags, status, description and cover of the manga
+func (m *Mangadex) MangaDetails(ctx context.Context, manga *source.Manga) error {
+	params := url.Values{}
+	params.Add("ids[]", manga.ID)
+	params.Set("limit", "1")
+	for _, rel := range []string{mangodex.AuthorRel, mangodex.ArtistRel, mangodex.CoverArtRel} {
+		params.Add("includes[]", rel)
+	}
+
+	// otherwise manga would not be found if it is not safe
+	for _, rating := range []string{mangodex.Safe, mangodex.Suggestive, mangodex.Erotica, mangodex.Porn} {
+		params.Add("contentRating[]", rating)
+	}
+
+	mangaList, err := m.client.Manga.GetMangaListContext(withSource(ctx), params)
+	if err != nil {
+		return err
+	}
+
+	if len(mangaList.Data) == 0 {
+		return fmt.Errorf("manga %s not found on %s", manga.ID, Name)
+	}
+
+	details := mangaList.Data[0]
+	attributes := details.Attributes
+	language := titleLanguage()
+
+	manga.Metadata.Summary = strings.TrimSpace(details.GetDescription(language))
+
+	if attributes.Status != nil {
+		if status, ok := statuses[*attributes.Status]; ok {
+			manga.Metadata.Status = status
+		}
+	}
+
+	if attributes.Year != nil {
+		manga.Metadata.StartDate.Year = *attributes.Year
+	}
+
+	manga.Metadata.Tags = make([]string, 0)
+	manga.Metadata.Genres = make([]string, 0)
+	for _, tag := range attributes.Tags {
+		name := tag.GetName("en")
+
+		// genres are tags of the genre group, the rest are just tags
+		if tag.Attributes.Group == "genre" {
+			manga.Metadata.Genres = append(manga.Metadata.Genres, name)
+		} else {
+			manga.Metadata.Tags = append(manga.Metadata.Tags, name)
+		}
+	}
+
+	manga.Metadata.Synonyms = make([]string, 0)
+	for _, title := range attributes.AltTitles.Values {
+		manga.Metadata.Synonyms = append(manga.Metadata.Synonyms, title)
+	}
+	slices.Sort(manga.Metadata.Synonyms)
+
+	manga.Metadata.Staff.Story = make([]string, 0)
+	manga.Metadata.Staff.Art = make([]string, 0)
+
+	for _, rel := range details.Relationships {
+		switch rel.Type {
+		case mangodex.AuthorRel:
+			if author, ok := rel.Attributes.(*mangodex.AuthorAttributes); ok {
+				manga.Metadata.Staff.Story = append(manga.Metadata.Staff.Story, author.Name)
+			}
+		case mangodex.ArtistRel:
+			if name := rawAttribute(rel, "name"); name != "" {
+				manga.Metadata.Staff.Art = append(manga.Metadata.Staff.Art, name)
+			}
+		case mangodex.CoverArtRel:
+			if fileName := rawAttribute(rel, "fileName"); fileName != "" {
+				cover := fmt.Sprintf("https://uploads.mangadex.org/covers/%s/%s", manga.ID, fileName)
+				manga.Metadata.Cover.ExtraLarge = cover
+				manga.Metadata.Cover.Large = cover + ".512.jpg"
+				manga.Metadata.Cover.Medium = cover + ".256.jpg"
+			}
+		}
+	}
+
+	manga.Metadata.URLs = []string{manga.URL}
+
+	return nil
+}
+
+// rawAttribute returns the string attribute of the relationship
+// that mangodex does not decode by itself
+func rawAttribute(rel mangodex.Relationship, name string) string {
+	raw, ok := rel.Attributes.(*json.RawMessage)
+	if !ok || raw == nil {
+		return ""
+	}
+
+	var attributes map[string]any
+	if err := json.Unmarshal(*raw, &attributes); err != nil {
+		return ""
+	}
+
+	value, _ := attributes[name].(string)
+	return value
+}
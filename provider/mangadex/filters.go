@@ -0,0 +1,137 @@
+package mangadex
+
+import (
+	"context"
+	"github.com/darylhjd/mangodex"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+	"golang.org/x/exp/slices"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// tagList is a response of the tags endpoint
+type tagList struct {
+	Result string         `json:"result"`
+	Data   []mangodex.Tag `json:"data"`
+}
+
+func (t *tagList) GetResult() string {
+	return t.Result
+}
+
+// tags returns all the tags available on Mangadex
+func (m *Mangadex) tags() ([]source.FilterOption, error) {
+	if cached, ok := m.cache.tags.Get("tags").Get(); ok {
+		return cached, nil
+	}
+
+	var list tagList
+	err := m.client.RequestAndDecode(withSource(context.Background()), http.MethodGet, mangodex.BaseAPI+"/manga/tag", nil, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	options := lo.Map(list.Data, func(tag mangodex.Tag, _ int) source.FilterOption {
+		return source.FilterOption{
+			Name:  tag.GetName("en"),
+			Value: tag.ID,
+		}
+	})
+
+	slices.SortFunc(options, func(a, b source.FilterOption) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	_ = m.cache.tags.Set("tags", options)
+	return options, nil
+}
+
+// SearchFilters returns filters supported by Mangadex
+func (m *Mangadex) SearchFilters() ([]*source.Filter, error) {
+	tags, err := m.tags()
+	if err != nil {
+		return nil, err
+	}
+
+	options := func(values ...string) []source.FilterOption {
+		return lo.Map(values, func(value string, _ int) source.FilterOption {
+			return source.FilterOption{Name: value, Value: value}
+		})
+	}
+
+	return []*source.Filter{
+		{
+			Key:      source.FilterTags,
+			Name:     "Tags",
+			Multiple: true,
+			Options:  tags,
+		},
+		{
+			Key:      source.FilterStatus,
+			Name:     "Status",
+			Multiple: true,
+			Options:  options(mangodex.OngoingStatus, mangodex.CompletedStatus, mangodex.HiatusStatus, mangodex.CancelledStatus),
+		},
+		{
+			Key:      source.FilterDemographic,
+			Name:     "Demographic",
+			Multiple: true,
+			Options:  options(mangodex.ShonenDemographic, mangodex.ShoujoDemographic, mangodex.JoseiDemographic, mangodex.SeinenDemograpic, "none"),
+		},
+		{
+			Key:      source.FilterContentRating,
+			Name:     "Content rating",
+			Multiple: true,
+			Options:  options(mangodex.Safe, mangodex.Suggestive, mangodex.Erotica, mangodex.Porn),
+		},
+		{
+			Key:      source.FilterLanguage,
+			Name:     "Translated language",
+			Multiple: true,
+		},
+		{
+			Key:  source.FilterYear,
+			Name: "Year",
+		},
+	}, nil
+}
+
+// applyFilters sets search parameters according to the filters
+func applyFilters(params url.Values, filters source.Filters) {
+	for _, tag := range filters[source.FilterTags] {
+		params.Add("includedTags[]", tag)
+	}
+
+	for _, status := range filters[source.FilterStatus] {
+		params.Add("status[]", status)
+	}
+
+	for _, demographic := range filters[source.FilterDemographic] {
+		params.Add("publicationDemographic[]", demographic)
+	}
+
+	for _, language := range filters[source.FilterLanguage] {
+		params.Add("availableTranslatedLanguage[]", language)
+	}
+
+	if year := filters.Get(source.FilterYear); year != "" {
+		params.Set("year", year)
+	}
+
+	ratings := filters[source.FilterContentRating]
+	if len(ratings) == 0 {
+		ratings = []string{mangodex.Safe, mangodex.Suggestive}
+
+		if viper.GetBool(key.MangadexNSFW) {
+			ratings = append(ratings, mangodex.Porn, mangodex.Erotica)
+		}
+	}
+
+	for _, rating := range ratings {
+		params.Add("contentRating[]", rating)
+	}
+}
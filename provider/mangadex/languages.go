@@ -0,0 +1,93 @@
+package mangadex
+
+import (
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// anyLanguage accepts chapters in any language
+const anyLanguage = "any"
+
+// parseLanguages splits the languages given either as a list or as comma separated values
+func parseLanguages(values []string) []string {
+	var languages []string
+	for _, value := range values {
+		for _, language := range strings.Split(value, ",") {
+			if language = strings.TrimSpace(language); language != "" {
+				languages = append(languages, language)
+			}
+		}
+	}
+
+	if len(languages) == 0 {
+		return []string{anyLanguage}
+	}
+
+	return languages
+}
+
+// preferredLanguages returns the languages in order of preference
+func preferredLanguages() []string {
+	return parseLanguages(viper.GetStringSlice(key.MangadexLanguage))
+}
+
+// mangaLanguages returns the languages of the manga chapters in order of preference.
+// Falls back to the preferred languages if the manga has no override
+func mangaLanguages(manga *source.Manga) []string {
+	for _, entry := range viper.GetStringSlice(key.MangadexMangaLanguage) {
+		name, languages, ok := strings.Cut(entry, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), manga.Name) {
+			return parseLanguages(strings.Split(languages, "|"))
+		}
+	}
+
+	return preferredLanguages()
+}
+
+// titleLanguage is the language of the titles and descriptions
+func titleLanguage() string {
+	return preferredLanguages()[0]
+}
+
+// languageRank returns the position of the language in the preferred ones.
+// Languages that are not preferred are ranked as "any", if it is present
+func languageRank(languages []string, language string) (int, bool) {
+	if i := lo.IndexOf(languages, language); i != -1 {
+		return i, true
+	}
+
+	if i := lo.IndexOf(languages, anyLanguage); i != -1 {
+		return i, true
+	}
+
+	return 0, false
+}
+
+// selectLanguages keeps the chapters in the best available language for each chapter number.
+// Chapters in the languages that are not preferred are dropped
+func selectLanguages(chapters []*source.Chapter, languages []string) []*source.Chapter {
+	best := make(map[string]int)
+	for _, chapter := range chapters {
+		rank, ok := languageRank(languages, chapter.Language)
+		if !ok {
+			continue
+		}
+
+		if current, ok := best[chapter.Number]; !ok || rank < current {
+			best[chapter.Number] = rank
+		}
+	}
+
+	return lo.Filter(chapters, func(chapter *source.Chapter, _ int) bool {
+		rank, ok := languageRank(languages, chapter.Language)
+		if !ok {
+			return false
+		}
+
+		// chapters without numbers can not be matched with the others
+		return chapter.Number == "" || rank == best[chapter.Number]
+	})
+}
@@ -0,0 +1,63 @@
+package mangadex
+
+import (
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"testing"
+)
+
+func TestSelectLanguages(t *testing.T) {
+	Convey("Given chapters in different languages", t, func() {
+		chapters := []*source.Chapter{
+			{Name: "1 en", Number: "1", Language: "en"},
+			{Name: "1 fr", Number: "1", Language: "fr"},
+			{Name: "2 fr", Number: "2", Language: "fr"},
+			{Name: "2 de", Number: "2", Language: "de"},
+			{Name: "3 de", Number: "3", Language: "de"},
+			{Name: "oneshot fr", Language: "fr"},
+		}
+
+		names := func(chapters []*source.Chapter) []string {
+			return lo.Map(chapters, func(chapter *source.Chapter, _ int) string {
+				return chapter.Name
+			})
+		}
+
+		Convey("When the languages are ordered", func() {
+			selected := selectLanguages(chapters, []string{"en", "fr"})
+
+			Convey("The best available language should be chosen for each chapter", func() {
+				So(names(selected), ShouldResemble, []string{"1 en", "2 fr", "oneshot fr"})
+			})
+		})
+
+		Convey("When any language is accepted as a fallback", func() {
+			selected := selectLanguages(chapters, []string{"en", anyLanguage})
+
+			Convey("Chapters missing in the preferred language should be kept", func() {
+				So(names(selected), ShouldResemble, []string{"1 en", "2 fr", "2 de", "3 de", "oneshot fr"})
+			})
+		})
+	})
+}
+
+func TestMangaLanguages(t *testing.T) {
+	Convey("Given preferred languages", t, func() {
+		viper.Set(key.MangadexLanguage, []string{"en,es-la", "fr"})
+		viper.Set(key.MangadexMangaLanguage, []string{"One Piece=es-la|en"})
+		defer viper.Set(key.MangadexLanguage, []string{"en"})
+		defer viper.Set(key.MangadexMangaLanguage, []string{})
+
+		Convey("The global languages should be used by default", func() {
+			So(mangaLanguages(&source.Manga{Name: "Death Note"}), ShouldResemble, []string{"en", "es-la", "fr"})
+			So(titleLanguage(), ShouldEqual, "en")
+		})
+
+		Convey("The manga override should be used when present", func() {
+			So(mangaLanguages(&source.Manga{Name: "one piece"}), ShouldResemble, []string{"es-la", "en"})
+		})
+	})
+}
@@ -1,8 +1,11 @@
 package mangadex
 
 import (
+	"context"
 	"github.com/darylhjd/mangodex"
+	"github.com/preetbiswas12/Kage/network"
 	"github.com/preetbiswas12/Kage/source"
+	"time"
 )
 
 const (
@@ -13,8 +16,9 @@ const (
 type Mangadex struct {
 	client *mangodex.DexClient
 	cache  struct {
-		mangas   *cacher[[]*source.Manga]
+		mangas   *cacher[*source.SearchPage]
 		chapters *cacher[[]*source.Chapter]
+		tags     *cacher[[]source.FilterOption]
 	}
 }
 
@@ -26,13 +30,20 @@ func (*Mangadex) ID() string {
 	return ID
 }
 
+// withSource marks the requests made with the context as the ones of Mangadex,
+// so that its network settings, such as the proxy, apply to them whoever calls the source
+func withSource(ctx context.Context) context.Context {
+	return network.WithSource(ctx, Name)
+}
+
 func New() *Mangadex {
 	dex := &Mangadex{
 		client: mangodex.NewDexClient(),
 	}
 
-	dex.cache.mangas = newCacher[[]*source.Manga](ID + "_mangas")
-	dex.cache.chapters = newCacher[[]*source.Chapter](ID + "_chapters")
+	dex.cache.mangas = newCacher[*source.SearchPage](ID+"_search_pages", time.Hour*24)
+	dex.cache.chapters = newCacher[[]*source.Chapter](ID+"_chapters", chaptersLifetime)
+	dex.cache.tags = newCacher[[]source.FilterOption](ID+"_tags", time.Hour*24)
 
 	return dex
 }
@@ -2,44 +2,131 @@ package mangadex
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/darylhjd/mangodex"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/log"
+	"github.com/preetbiswas12/Kage/network"
 	"github.com/preetbiswas12/Kage/source"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/url"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
+const (
+	// qualityData is the original quality of the pages
+	qualityData = "data"
+	// qualityDataSaver is the compressed quality of the pages
+	qualityDataSaver = "data-saver"
+)
+
+// reportTimeout bounds reports to the MangaDex@Home network, so that they never pile up
+const reportTimeout = 10 * time.Second
+
 func (m *Mangadex) PagesOf(chapter *source.Chapter) ([]*source.Page, error) {
-	downloader, err := m.client.AtHome.NewMDHomeClient(chapter.ID, "data", false)
+	return m.PagesOfContext(context.Background(), chapter)
+}
+
+// PagesOfContext returns the addresses of the chapter pages on the MangaDex@Home server.
+// Pages are downloaded later, same as for the other sources
+func (m *Mangadex) PagesOfContext(ctx context.Context, chapter *source.Chapter) ([]*source.Page, error) {
+	u, _ := url.Parse(mangodex.BaseAPI)
+	u.Path = fmt.Sprintf(mangodex.GetMDHomeURLPath, chapter.ID)
+
+	var server mangodex.MDHomeServerResponse
+	err := m.client.RequestAndDecode(withSource(ctx), http.MethodGet, u.String(), nil, &server)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(downloader.Pages) == 0 {
-		return nil, errors.New("there were no pages for this chapter")
+	quality := viper.GetString(key.MangadexQuality)
+	if quality != qualityData && quality != qualityDataSaver {
+		return nil, fmt.Errorf("unknown quality %q, expected %s or %s", quality, qualityData, qualityDataSaver)
 	}
 
-	var pages = make([]*source.Page, len(downloader.Pages))
+	names := pageNames(&server, quality)
 
-	for i, name := range downloader.Pages {
-		image, err := downloader.GetChapterPage(name)
-		if err != nil {
-			return nil, err
-		}
+	if len(names) == 0 {
+		return nil, errors.New("there were no pages for this chapter")
+	}
 
-		if len(image) == 0 {
-			return nil, errors.New("image is empty")
-		}
+	var pages = make([]*source.Page, len(names))
 
-		page := source.Page{
+	for i, name := range names {
+		pages[i] = &source.Page{
+			URL:       strings.Join([]string{server.BaseURL, quality, server.Chapter.Hash, name}, "/"),
 			Index:     uint16(i),
 			Chapter:   chapter,
 			Extension: filepath.Ext(name),
-			Contents:  bytes.NewBuffer(image),
-			Size:      uint64(len(image)),
 		}
-
-		pages[i] = &page
 	}
 
 	chapter.Pages = pages
+	chapter.Quality = quality
 	return pages, nil
 }
+
+// pageNames returns file names of the pages in the given quality
+func pageNames(server *mangodex.MDHomeServerResponse, quality string) []string {
+	if quality == qualityDataSaver {
+		return server.Chapter.DataSaver
+	}
+
+	return server.Chapter.Data
+}
+
+// report is the payload of the MangaDex@Home report endpoint
+type report struct {
+	URL      string `json:"url"`
+	Success  bool   `json:"success"`
+	Bytes    uint64 `json:"bytes"`
+	Duration int64  `json:"duration"`
+	Cached   bool   `json:"cached"`
+}
+
+// ReportPage reports the page download result to the MangaDex@Home network, as the API requires.
+// Pages served by MangaDex itself are not reported. The report is sent in the background
+func (m *Mangadex) ReportPage(page *source.Page, result source.PageReport) {
+	u, err := url.Parse(page.URL)
+	if err != nil || u.Hostname() == "mangadex.org" || strings.HasSuffix(u.Hostname(), ".mangadex.org") {
+		return
+	}
+
+	body, err := json.Marshal(report{
+		URL:      page.URL,
+		Success:  result.Success,
+		Bytes:    result.Size,
+		Duration: result.Duration.Milliseconds(),
+		Cached:   result.Cached,
+	})
+	if err != nil {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(withSource(context.Background()), reportTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, mangodex.MDHomeReportURL, bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := network.Client.Do(req)
+		if err != nil {
+			log.Warnf("failed to report page #%d to MangaDex@Home: %s", page.Index, err)
+			return
+		}
+
+		util.Ignore(resp.Body.Close)
+	}()
+}
@@ -1,19 +1,52 @@
 package mangadex
 
 import (
+	"context"
 	"fmt"
-	"github.com/darylhjd/mangodex"
-	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/source"
-	"github.com/spf13/viper"
-	"log"
 	"net/url"
 	"strconv"
 )
 
+const (
+	// searchLimit is the number of mangas requested per search page
+	searchLimit = 100
+	// searchWindow is the maximum offset + limit allowed by the Mangadex API
+	searchWindow = 10_000
+)
+
 func (m *Mangadex) Search(query string) ([]*source.Manga, error) {
-	if cached, ok := m.cache.mangas.Get(query).Get(); ok {
-		for _, manga := range cached {
+	return m.SearchContext(context.Background(), query)
+}
+
+func (m *Mangadex) SearchContext(ctx context.Context, query string) ([]*source.Manga, error) {
+	page, err := m.SearchPage(ctx, query, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Mangas, nil
+}
+
+// SearchPage searches for mangas starting from the offset given by the token.
+func (m *Mangadex) SearchPage(ctx context.Context, query, token string) (*source.SearchPage, error) {
+	return m.SearchFilteredPage(ctx, query, nil, token)
+}
+
+// SearchFilteredPage searches for mangas matching the filters starting from the offset given by the token.
+func (m *Mangadex) SearchFilteredPage(ctx context.Context, query string, filters source.Filters, token string) (*source.SearchPage, error) {
+	offset, err := parseOffset(token)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("%s#%d", query, offset)
+	if len(filters) > 0 {
+		cacheKey += "?" + filters.Encode()
+	}
+
+	if cached, ok := m.cache.mangas.Get(cacheKey).Get(); ok {
+		for _, manga := range cached.Mangas {
 			manga.Source = m
 		}
 
@@ -21,25 +54,41 @@ func (m *Mangadex) Search(query string) ([]*source.Manga, error) {
 	}
 
 	params := url.Values{}
-	params.Set("limit", strconv.Itoa(100))
+	applyFilters(params, filters)
 
-	ratings := []string{mangodex.Safe, mangodex.Suggestive}
+	params.Set("order[followedCount]", "desc")
+	params.Set("title", query)
 
-	for _, rating := range ratings {
-		params.Add("contentRating[]", rating)
+	page, err := m.mangaListPage(ctx, params, offset)
+	if err != nil {
+		return nil, err
 	}
 
-	if viper.GetBool(key.MangadexNSFW) {
-		params.Add("contentRating[]", mangodex.Porn)
-		params.Add("contentRating[]", mangodex.Erotica)
+	_ = m.cache.mangas.Set(cacheKey, page)
+	return page, nil
+}
+
+// parseOffset parses the page token, which is the offset of the page
+func parseOffset(token string) (int, error) {
+	if token == "" {
+		return 0, nil
 	}
 
-	params.Set("order[followedCount]", "desc")
-	params.Set("title", query)
+	offset, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+
+	return offset, nil
+}
+
+// mangaListPage requests the page of mangas starting from the offset
+func (m *Mangadex) mangaListPage(ctx context.Context, params url.Values, offset int) (*source.SearchPage, error) {
+	params.Set("limit", strconv.Itoa(searchLimit))
+	params.Set("offset", strconv.Itoa(offset))
 
-	mangaList, err := m.client.Manga.GetMangaList(params)
+	mangaList, err := m.client.Manga.GetMangaListContext(withSource(ctx), params)
 	if err != nil {
-		log.Fatalln(err)
 		return nil, err
 	}
 
@@ -47,9 +96,9 @@ func (m *Mangadex) Search(query string) ([]*source.Manga, error) {
 
 	for i, manga := range mangaList.Data {
 		m := source.Manga{
-			Name:   manga.GetTitle(viper.GetString(key.MangadexLanguage)),
+			Name:   manga.GetTitle(titleLanguage()),
 			URL:    fmt.Sprintf("https://mangadex.org/title/%s", manga.ID),
-			Index:  uint16(i),
+			Index:  uint16(offset + i),
 			ID:     manga.ID,
 			Source: m,
 		}
@@ -57,6 +106,10 @@ func (m *Mangadex) Search(query string) ([]*source.Manga, error) {
 		mangas = append(mangas, &m)
 	}
 
-	_ = m.cache.mangas.Set(query, mangas)
-	return mangas, nil
+	page := &source.SearchPage{Mangas: mangas}
+	if next := offset + len(mangaList.Data); len(mangaList.Data) > 0 && next < mangaList.Total && next+searchLimit <= searchWindow {
+		page.Next = strconv.Itoa(next)
+	}
+
+	return page, nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/preetbiswas12/Kage/provider/generic"
+	"github.com/preetbiswas12/Kage/source"
 )
 
 var Config = &generic.Configuration{
@@ -38,6 +39,15 @@ var Config = &generic.Configuration{
 			return selection.Find("img").AttrOr("data-src", "")
 		},
 	},
+	BrowseURLs: map[source.BrowseList]string{
+		source.BrowseRecent: "https://mangapill.com/mangas/new",
+	},
+	MangaNextPageExtractor: &generic.Extractor{
+		Selector: "a[href*='page=']:contains('Next')",
+		URL: func(selection *goquery.Selection) string {
+			return selection.AttrOr("href", "")
+		},
+	},
 	ChapterExtractor: &generic.Extractor{
 		Selector: "div[data-filter-list] a",
 		Name: func(selection *goquery.Selection) string {
@@ -56,4 +66,14 @@ var Config = &generic.Configuration{
 			return selection.AttrOr("data-src", "")
 		},
 	},
+	DetailsExtractor: &generic.DetailsExtractor{
+		Summary: func(selection *goquery.Selection) string {
+			return selection.Find("p.text-sm.text--secondary").First().Text()
+		},
+		Genres: func(selection *goquery.Selection) []string {
+			return selection.Find("a[href*='genre=']").Map(func(_ int, s *goquery.Selection) string {
+				return strings.TrimSpace(s.Text())
+			})
+		},
+	},
 }
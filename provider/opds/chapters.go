@@ -0,0 +1,71 @@
+package opds
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/source"
+)
+
+// maxFeedPages bounds the pages of the chapters feed followed, in case the catalog loops
+const maxFeedPages = 100
+
+func (o *OPDS) ChaptersOf(manga *source.Manga) ([]*source.Chapter, error) {
+	return o.ChaptersOfContext(context.Background(), manga)
+}
+
+func (o *OPDS) ChaptersOfContext(ctx context.Context, manga *source.Manga) ([]*source.Chapter, error) {
+	var chapters []*source.Chapter
+
+	// standalone publication is the only chapter of itself
+	if e, ok := o.publication(manga.URL); ok {
+		chapters = append(chapters, o.chapter(manga, e, manga.URL, 1))
+	} else {
+		address := manga.URL
+		for page := 0; address != "" && page < maxFeedPages; page++ {
+			f, err := o.feed(ctx, address)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, e := range f.entries {
+				publication, err := publicationAddress(&e)
+				if err != nil {
+					continue
+				}
+
+				o.remember(publication, e)
+				chapters = append(chapters, o.chapter(manga, e, publication, len(chapters)+1))
+			}
+
+			address = ""
+			if next, ok := f.find(relNext); ok {
+				address = next.href
+			}
+		}
+	}
+
+	source.SortChapters(chapters)
+	for i, chapter := range chapters {
+		chapter.Index = uint16(i + 1)
+	}
+
+	manga.Chapters = chapters
+	return chapters, nil
+}
+
+// chapter converts the publication entry to the chapter with the given index in the feed
+func (o *OPDS) chapter(manga *source.Manga, e entry, address string, index int) *source.Chapter {
+	chapter := &source.Chapter{
+		Name:   e.title,
+		URL:    address,
+		ID:     e.id,
+		Number: source.ParseChapterNumber(e.title),
+		Index:  uint16(index),
+		Manga:  manga,
+	}
+
+	if stream, ok := e.stream(); ok {
+		chapter.PagesCount = stream.count
+	}
+
+	return chapter
+}
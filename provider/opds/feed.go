@@ -0,0 +1,299 @@
+package opds
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strings"
+)
+
+// Link relations and types used by the catalogs
+const (
+	relAcquisition = "http://opds-spec.org/acquisition"
+	relStream      = "http://vaemendis.net/opds-pse/stream"
+	relImage       = "http://opds-spec.org/image"
+	relSubsection  = "subsection"
+	relNext        = "next"
+	relSearch      = "search"
+
+	typeCatalog    = "profile=opds-catalog"
+	typeOPDS2      = "application/opds+json"
+	typeOpenSearch = "application/opensearchdescription+xml"
+)
+
+// link is a link of a feed or an entry, same for both OPDS versions
+type link struct {
+	rel       string
+	href      string
+	mediaType string
+	// count is the number of pages of the stream link
+	count int
+}
+
+// entry is an entry of a feed, either a navigation one or a publication
+type entry struct {
+	id      string
+	title   string
+	summary string
+	authors []string
+	links   []link
+}
+
+// find returns the first link of the entry that matches
+func (e *entry) find(match func(link) bool) (link, bool) {
+	for _, l := range e.links {
+		if match(l) {
+			return l, true
+		}
+	}
+
+	return link{}, false
+}
+
+func (e *entry) navigation() (link, bool) {
+	return e.find(func(l link) bool {
+		return l.rel == relSubsection || strings.Contains(l.mediaType, typeCatalog) || l.mediaType == typeOPDS2
+	})
+}
+
+func (e *entry) acquisition() (link, bool) {
+	return e.find(func(l link) bool {
+		return strings.HasPrefix(l.rel, relAcquisition)
+	})
+}
+
+func (e *entry) stream() (link, bool) {
+	return e.find(func(l link) bool {
+		return l.rel == relStream && l.count > 0
+	})
+}
+
+func (e *entry) image() (link, bool) {
+	return e.find(func(l link) bool {
+		return l.rel == relImage
+	})
+}
+
+// feed is a catalog feed, same for both OPDS versions
+type feed struct {
+	links   []link
+	entries []entry
+}
+
+func (f *feed) find(rel string) (link, bool) {
+	for _, l := range f.links {
+		if l.rel == rel {
+			return l, true
+		}
+	}
+
+	return link{}, false
+}
+
+// parseFeed parses the OPDS 1.2 (Atom) or OPDS 2.0 (JSON) feed.
+// Links are resolved against the feed address
+func parseFeed(base *url.URL, data []byte) (*feed, error) {
+	var (
+		f   *feed
+		err error
+	)
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		f, err = parseJSONFeed(data)
+	} else {
+		f, err = parseAtomFeed(data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resolve := func(links []link) {
+		for i, l := range links {
+			links[i].href = resolveHref(base, l.href)
+		}
+	}
+
+	resolve(f.links)
+	for _, e := range f.entries {
+		resolve(e.links)
+	}
+
+	return f, nil
+}
+
+// resolveHref resolves the link against the base address.
+// Templates are kept as they are, so their placeholders are not escaped
+func resolveHref(base *url.URL, href string) string {
+	ref, err := url.Parse(href)
+	if err != nil || ref.IsAbs() {
+		return href
+	}
+
+	resolved := base.ResolveReference(ref)
+	// unescape the placeholders of the templates
+	return strings.NewReplacer("%7B", "{", "%7D", "}").Replace(resolved.String())
+}
+
+type atomLink struct {
+	Rel   string `xml:"rel,attr"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr"`
+	Count int    `xml:"http://vaemendis.net/opds-pse/ns count,attr"`
+}
+
+type atomFeed struct {
+	Links   []atomLink `xml:"link"`
+	Entries []struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Summary string `xml:"summary"`
+		Content string `xml:"content"`
+		Authors []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Links []atomLink `xml:"link"`
+	} `xml:"entry"`
+}
+
+func (l atomLink) link() link {
+	return link{rel: l.Rel, href: l.Href, mediaType: l.Type, count: l.Count}
+}
+
+func parseAtomFeed(data []byte) (*feed, error) {
+	var atom atomFeed
+	if err := xml.Unmarshal(data, &atom); err != nil {
+		return nil, err
+	}
+
+	f := &feed{}
+	for _, l := range atom.Links {
+		f.links = append(f.links, l.link())
+	}
+
+	for _, e := range atom.Entries {
+		summary := e.Summary
+		if summary == "" {
+			summary = e.Content
+		}
+
+		parsed := entry{
+			id:      e.ID,
+			title:   strings.TrimSpace(e.Title),
+			summary: strings.TrimSpace(summary),
+		}
+
+		for _, author := range e.Authors {
+			parsed.authors = append(parsed.authors, author.Name)
+		}
+
+		for _, l := range e.Links {
+			parsed.links = append(parsed.links, l.link())
+		}
+
+		f.entries = append(f.entries, parsed)
+	}
+
+	return f, nil
+}
+
+// rels are link relations of OPDS 2.0, given either as a string or as a list
+type rels []string
+
+func (r *rels) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*r = rels{single}
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(r))
+}
+
+type jsonLink struct {
+	Rel   rels   `json:"rel"`
+	Href  string `json:"href"`
+	Type  string `json:"type"`
+	Title string `json:"title"`
+}
+
+type jsonPublication struct {
+	Metadata struct {
+		Identifier  string `json:"identifier"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	} `json:"metadata"`
+	Links  []jsonLink `json:"links"`
+	Images []jsonLink `json:"images"`
+}
+
+type jsonFeed struct {
+	Links        []jsonLink        `json:"links"`
+	Navigation   []jsonLink        `json:"navigation"`
+	Publications []jsonPublication `json:"publications"`
+	Groups       []struct {
+		Navigation   []jsonLink        `json:"navigation"`
+		Publications []jsonPublication `json:"publications"`
+	} `json:"groups"`
+}
+
+// links converts the link to the links of each relation
+func (l jsonLink) links() []link {
+	if len(l.Rel) == 0 {
+		return []link{{href: l.Href, mediaType: l.Type}}
+	}
+
+	links := make([]link, len(l.Rel))
+	for i, rel := range l.Rel {
+		links[i] = link{rel: rel, href: l.Href, mediaType: l.Type}
+	}
+
+	return links
+}
+
+func parseJSONFeed(data []byte) (*feed, error) {
+	var j jsonFeed
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+
+	f := &feed{}
+	for _, l := range j.Links {
+		f.links = append(f.links, l.links()...)
+	}
+
+	navigation, publications := j.Navigation, j.Publications
+	for _, group := range j.Groups {
+		navigation = append(navigation, group.Navigation...)
+		publications = append(publications, group.Publications...)
+	}
+
+	for _, n := range navigation {
+		f.entries = append(f.entries, entry{
+			id:    n.Href,
+			title: n.Title,
+			links: []link{{rel: relSubsection, href: n.Href, mediaType: n.Type}},
+		})
+	}
+
+	for _, p := range publications {
+		parsed := entry{
+			id:      p.Metadata.Identifier,
+			title:   p.Metadata.Title,
+			summary: p.Metadata.Description,
+		}
+
+		for _, l := range p.Links {
+			parsed.links = append(parsed.links, l.links()...)
+		}
+
+		for _, image := range p.Images {
+			parsed.links = append(parsed.links, link{rel: relImage, href: image.Href, mediaType: image.Type})
+		}
+
+		f.entries = append(f.entries, parsed)
+	}
+
+	return f, nil
+}
@@ -0,0 +1,154 @@
+package opds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/network"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/spf13/viper"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const (
+	Name = "OPDS"
+	ID   = Name + " built-in"
+)
+
+// OPDS is a source of the mangas in the OPDS catalog.
+// Series are the navigation entries of the catalog and chapters are its publications
+type OPDS struct {
+	root               string
+	username, password string
+
+	mutex sync.Mutex
+	// searchTemplate is the search address with the {searchTerms} placeholder
+	searchTemplate string
+	// publications are entries of the found chapters and standalone publications by their addresses
+	publications map[string]entry
+}
+
+func (*OPDS) Name() string {
+	return Name
+}
+
+func (*OPDS) ID() string {
+	return ID
+}
+
+// New creates the source of the configured catalog
+func New() (*OPDS, error) {
+	root := viper.GetString(key.OPDSURL)
+	if root == "" {
+		return nil, fmt.Errorf("%s is not set", key.OPDSURL)
+	}
+
+	return &OPDS{
+		root:         root,
+		username:     viper.GetString(key.OPDSUsername),
+		password:     viper.GetString(key.OPDSPassword),
+		publications: make(map[string]entry),
+	}, nil
+}
+
+// sameOrigin reports whether the address is on the catalog server.
+// Links of the catalog may point to other hosts, such as CDNs, that must not get the credentials
+func (o *OPDS) sameOrigin(address *url.URL) bool {
+	root, err := url.Parse(o.root)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(root.Scheme, address.Scheme) && strings.EqualFold(root.Host, address.Host)
+}
+
+// get requests the catalog resource with the credentials if it is on the catalog server
+func (o *OPDS) get(ctx context.Context, address string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(network.WithSource(ctx, Name), http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", network.UserAgent())
+	if (o.username != "" || o.password != "") && o.sameOrigin(req.URL) {
+		req.SetBasicAuth(o.username, o.password)
+	}
+
+	resp, err := network.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		util.Ignore(resp.Body.Close)
+		return nil, fmt.Errorf("%s: %s", address, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// read requests the catalog resource and reads it whole
+func (o *OPDS) read(ctx context.Context, address string) ([]byte, error) {
+	resp, err := o.get(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	defer util.Ignore(resp.Body.Close)
+	return io.ReadAll(resp.Body)
+}
+
+// feed requests and parses the feed
+func (o *OPDS) feed(ctx context.Context, address string) (*feed, error) {
+	base, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := o.read(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parseFeed(base, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed %s: %w", address, err)
+	}
+
+	return f, nil
+}
+
+// remember keeps the publication entries to get their pages later
+func (o *OPDS) remember(address string, e entry) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.publications[address] = e
+}
+
+// publication returns the remembered publication entry
+func (o *OPDS) publication(address string) (entry, bool) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	e, ok := o.publications[address]
+	return e, ok
+}
+
+// publicationAddress is the address that identifies the publication
+func publicationAddress(e *entry) (string, error) {
+	if l, ok := e.acquisition(); ok {
+		return l.href, nil
+	}
+
+	if l, ok := e.stream(); ok {
+		return l.href, nil
+	}
+
+	return "", errors.New("publication has no acquisition links")
+}
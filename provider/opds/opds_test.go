@@ -0,0 +1,226 @@
+package opds
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/preetbiswas12/Kage/util"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const (
+	rootFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<link rel="search" type="application/opensearchdescription+xml" href="/opensearch"/>
+</feed>`
+	openSearch = `<OpenSearchDescription xmlns="http://a9.com/-/spec/opensearch/1.1/">
+	<Url type="application/atom+xml;profile=opds-catalog" template="/search?q={searchTerms}&amp;page={startPage?}"/>
+</OpenSearchDescription>`
+	searchFeed = `<feed xmlns="http://www.w3.org/2005/Atom">
+	<entry>
+		<id>series-1</id>
+		<title>One Piece</title>
+		<summary>Pirates</summary>
+		<author><name>Eiichiro Oda</name></author>
+		<link rel="subsection" type="application/atom+xml;profile=opds-catalog" href="/series/1"/>
+		<link rel="http://opds-spec.org/image" type="image/jpeg" href="/covers/1.jpg"/>
+	</entry>
+	<entry>
+		<id>book-9</id>
+		<title>One Piece Artbook</title>
+		<link rel="http://opds-spec.org/acquisition" type="application/vnd.comicbook+zip" href="/books/9/file"/>
+	</entry>
+</feed>`
+	seriesFeed = `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:pse="http://vaemendis.net/opds-pse/ns">
+	<link rel="next" href="/series/1?page=2"/>
+	<entry>
+		<id>book-2</id>
+		<title>Chapter 2</title>
+		<link rel="http://vaemendis.net/opds-pse/stream" type="image/png" pse:count="3" href="/books/2/pages/{pageNumber}?width={maxWidth}"/>
+		<link rel="http://opds-spec.org/acquisition" type="application/vnd.comicbook+zip" href="/books/2/file"/>
+	</entry>
+</feed>`
+	seriesFeedNext = `<feed xmlns="http://www.w3.org/2005/Atom">
+	<entry>
+		<id>book-1</id>
+		<title>Chapter 1</title>
+		<link rel="http://opds-spec.org/acquisition" type="application/vnd.comicbook+zip" href="/books/1/file"/>
+	</entry>
+</feed>`
+)
+
+func catalog() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/opds":
+			_, _ = w.Write([]byte(rootFeed))
+		case "/opensearch":
+			_, _ = w.Write([]byte(openSearch))
+		case "/search":
+			_, _ = w.Write([]byte(searchFeed))
+		case "/series/1":
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(seriesFeedNext))
+			} else {
+				_, _ = w.Write([]byte(seriesFeed))
+			}
+		case "/books/1/file":
+			_, _ = w.Write(lo.Must(util.Zip(map[string]string{
+				"002.jpg":       "second",
+				"001.jpg":       "first",
+				"ComicInfo.xml": "<ComicInfo/>",
+			})))
+		default:
+			_, _ = w.Write([]byte(r.URL.Path + "?" + r.URL.RawQuery))
+		}
+	}))
+}
+
+func TestOPDS(t *testing.T) {
+	Convey("Given an OPDS catalog", t, func() {
+		server := catalog()
+		defer server.Close()
+
+		viper.Set(key.OPDSURL, server.URL+"/opds")
+		viper.Set(key.OPDSUsername, "user")
+		viper.Set(key.OPDSPassword, "secret")
+		defer viper.Set(key.OPDSURL, "")
+
+		o, err := New()
+		So(err, ShouldBeNil)
+
+		Convey("When searching", func() {
+			mangas, err := o.Search("one piece")
+			So(err, ShouldBeNil)
+
+			Convey("Then series and standalone publications should be found", func() {
+				So(mangas, ShouldHaveLength, 2)
+				So(mangas[0].Name, ShouldEqual, "One Piece")
+				So(mangas[0].URL, ShouldEqual, server.URL+"/series/1")
+				So(mangas[0].Metadata.Summary, ShouldEqual, "Pirates")
+				So(mangas[0].Metadata.Cover.ExtraLarge, ShouldEqual, server.URL+"/covers/1.jpg")
+				So(mangas[1].URL, ShouldEqual, server.URL+"/books/9/file")
+			})
+
+			Convey("And getting the chapters of the series", func() {
+				chapters, err := o.ChaptersOf(mangas[0])
+				So(err, ShouldBeNil)
+
+				Convey("Then chapters of all the feed pages should be sorted", func() {
+					So(chapters, ShouldHaveLength, 2)
+					So(chapters[0].Name, ShouldEqual, "Chapter 1")
+					So(chapters[0].Index, ShouldEqual, 1)
+					So(chapters[1].Name, ShouldEqual, "Chapter 2")
+					So(chapters[1].PagesCount, ShouldEqual, 3)
+				})
+
+				Convey("Then streamed pages should be built from the template", func() {
+					pages, err := o.PagesOf(chapters[1])
+					So(err, ShouldBeNil)
+					So(pages, ShouldHaveLength, 3)
+					So(pages[2].URL, ShouldEqual, server.URL+"/books/2/pages/2?width=4096")
+					So(pages[2].Extension, ShouldEqual, ".png")
+
+					So(o.DownloadPage(context.Background(), pages[2]), ShouldBeNil)
+					So(pages[2].Contents.String(), ShouldEqual, "/books/2/pages/2?width=4096")
+				})
+
+				Convey("Then pages of the archive should be read in order", func() {
+					pages, err := o.PagesOf(chapters[0])
+					So(err, ShouldBeNil)
+					So(pages, ShouldHaveLength, 2)
+					So(pages[0].Contents.String(), ShouldEqual, "first")
+					So(pages[1].Contents.String(), ShouldEqual, "second")
+					So(pages[1].Extension, ShouldEqual, ".jpg")
+				})
+			})
+		})
+
+		Convey("When a link points to another host", func() {
+			var authorization string
+			cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = r.Header.Get("Authorization")
+			}))
+			defer cdn.Close()
+
+			resp, err := o.get(context.Background(), cdn.URL+"/books/2/file")
+			So(err, ShouldBeNil)
+			So(resp.Body.Close(), ShouldBeNil)
+
+			Convey("Then the credentials should not be sent", func() {
+				So(authorization, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When the credentials are wrong", func() {
+			o.password = "wrong"
+
+			Convey("Then searching should fail", func() {
+				_, err := o.Search("one piece")
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestParseFeed(t *testing.T) {
+	Convey("Given an OPDS 2.0 feed", t, func() {
+		data := []byte(`{
+			"links": [{"rel": "search", "href": "/search{?query}", "type": "application/opds+json", "templated": true}],
+			"navigation": [{"href": "/series/1", "title": "One Piece", "type": "application/opds+json"}],
+			"groups": [{"publications": [{
+				"metadata": {"identifier": "book-1", "title": "Chapter 1"},
+				"links": [{"rel": ["http://opds-spec.org/acquisition/open-access"], "href": "/books/1", "type": "application/vnd.comicbook+zip"}],
+				"images": [{"href": "/covers/1.jpg", "type": "image/jpeg"}]
+			}]}]
+		}`)
+		base := lo.Must(url.Parse("https://example.com/opds"))
+
+		Convey("When parsing it", func() {
+			f, err := parseFeed(base, data)
+			So(err, ShouldBeNil)
+
+			Convey("Then links should be resolved with templates kept", func() {
+				search, ok := f.find(relSearch)
+				So(ok, ShouldBeTrue)
+				So(search.href, ShouldEqual, "https://example.com/search{?query}")
+			})
+
+			Convey("Then navigation and publications should become entries", func() {
+				So(f.entries, ShouldHaveLength, 2)
+				_, ok := f.entries[0].navigation()
+				So(ok, ShouldBeTrue)
+				_, ok = f.entries[1].acquisition()
+				So(ok, ShouldBeTrue)
+
+				image, ok := f.entries[1].image()
+				So(ok, ShouldBeTrue)
+				So(image.href, ShouldEqual, "https://example.com/covers/1.jpg")
+			})
+		})
+	})
+}
+
+func TestStreamPages(t *testing.T) {
+	Convey("Given a stream link without a known type", t, func() {
+		stream := link{href: "/pages/{pageNumber}", count: 2}
+
+		Convey("Then pages should default to jpg", func() {
+			pages := streamPages(&source.Chapter{}, stream)
+			So(pages, ShouldHaveLength, 2)
+			So(pages[1].URL, ShouldEqual, "/pages/1")
+			So(pages[1].Extension, ShouldEqual, ".jpg")
+		})
+	})
+}
@@ -0,0 +1,158 @@
+package opds
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// streamWidth is the maximum width of the streamed pages.
+// Large enough to get the original images from the servers that resize them
+const streamWidth = 4096
+
+// imageExtensions are extensions of the page images by their media types
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+func (o *OPDS) PagesOf(chapter *source.Chapter) ([]*source.Page, error) {
+	return o.PagesOfContext(context.Background(), chapter)
+}
+
+// PagesOfContext returns the pages streamed with OPDS-PSE if the catalog supports it.
+// Otherwise, the publication archive is downloaded and its images are used as pages
+func (o *OPDS) PagesOfContext(ctx context.Context, chapter *source.Chapter) ([]*source.Page, error) {
+	var (
+		pages []*source.Page
+		err   error
+	)
+
+	e, ok := o.publication(chapter.URL)
+	if stream, hasStream := e.stream(); ok && hasStream {
+		pages = streamPages(chapter, stream)
+	} else {
+		pages, err = o.archivePages(ctx, chapter)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	chapter.Pages = pages
+	return pages, nil
+}
+
+// streamPages returns the pages of the OPDS-PSE stream link
+func streamPages(chapter *source.Chapter, stream link) []*source.Page {
+	extension, ok := imageExtensions[stream.mediaType]
+	if !ok {
+		extension = ".jpg"
+	}
+
+	pages := make([]*source.Page, stream.count)
+	for i := range pages {
+		pages[i] = &source.Page{
+			URL: strings.NewReplacer(
+				"{pageNumber}", strconv.Itoa(i),
+				"{maxWidth}", strconv.Itoa(streamWidth),
+			).Replace(stream.href),
+			Index:     uint16(i),
+			Chapter:   chapter,
+			Extension: extension,
+		}
+	}
+
+	return pages
+}
+
+// archivePages downloads the publication archive and reads its images
+func (o *OPDS) archivePages(ctx context.Context, chapter *source.Chapter) ([]*source.Page, error) {
+	data, err := o.read(ctx, chapter.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported publication format, only CBZ and ZIP are supported: %w", err)
+	}
+
+	files := lo.Filter(reader.File, func(file *zip.File, _ int) bool {
+		return !file.FileInfo().IsDir() && isImage(file.Name)
+	})
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no pages found in %s", chapter.URL)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	pages := make([]*source.Page, len(files))
+	for i, file := range files {
+		contents, err := readFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		// archive is already downloaded, so are its pages
+		pages[i] = &source.Page{
+			URL:       chapter.URL + "#" + file.Name,
+			Index:     uint16(i),
+			Chapter:   chapter,
+			Extension: filepath.Ext(file.Name),
+			Contents:  bytes.NewBuffer(contents),
+			Size:      uint64(len(contents)),
+		}
+	}
+
+	return pages, nil
+}
+
+func isImage(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return true
+	default:
+		return false
+	}
+}
+
+func readFile(file *zip.File) ([]byte, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// DownloadPage downloads the streamed page with the catalog credentials.
+// Pages of the archives are read when the archive is downloaded
+func (o *OPDS) DownloadPage(ctx context.Context, page *source.Page) error {
+	if page.Contents != nil {
+		return nil
+	}
+
+	contents, err := o.read(ctx, page.URL)
+	if err != nil {
+		return fmt.Errorf("failed to download page #%d: %w", page.Index, err)
+	}
+
+	page.Contents = bytes.NewBuffer(contents)
+	page.Size = uint64(len(contents))
+	return nil
+}
@@ -0,0 +1,161 @@
+package opds
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"github.com/preetbiswas12/Kage/source"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// optionalParameter matches optional OpenSearch parameters, such as {startPage?}
+var optionalParameter = regexp.MustCompile(`\{[^}]*\?}`)
+
+func (o *OPDS) Search(query string) ([]*source.Manga, error) {
+	return o.SearchContext(context.Background(), query)
+}
+
+func (o *OPDS) SearchContext(ctx context.Context, query string) ([]*source.Manga, error) {
+	page, err := o.SearchPage(ctx, query, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Mangas, nil
+}
+
+// SearchPage returns the page of search results. The token is the address of the next page
+func (o *OPDS) SearchPage(ctx context.Context, query, token string) (*source.SearchPage, error) {
+	address := token
+	if address == "" {
+		template, err := o.searchURL(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		escaped := url.QueryEscape(strings.TrimSpace(query))
+		address = strings.NewReplacer(
+			"{searchTerms}", escaped,
+			"{?query}", "?query="+escaped,
+			"{query}", escaped,
+		).Replace(template)
+		address = optionalParameter.ReplaceAllString(address, "")
+	}
+
+	f, err := o.feed(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &source.SearchPage{}
+	if next, ok := f.find(relNext); ok {
+		page.Next = next.href
+	}
+
+	for _, e := range f.entries {
+		if manga, ok := o.manga(e); ok {
+			manga.Index = uint16(len(page.Mangas))
+			page.Mangas = append(page.Mangas, manga)
+		}
+	}
+
+	return page, nil
+}
+
+// manga converts the entry to the manga.
+// Navigation entries are series, standalone publications become mangas of a single chapter
+func (o *OPDS) manga(e entry) (*source.Manga, bool) {
+	manga := &source.Manga{
+		Name:   e.title,
+		ID:     e.id,
+		Source: o,
+	}
+
+	if nav, ok := e.navigation(); ok {
+		manga.URL = nav.href
+	} else if address, err := publicationAddress(&e); err == nil {
+		manga.URL = address
+		o.remember(address, e)
+	} else {
+		return nil, false
+	}
+
+	manga.Metadata.Summary = e.summary
+	manga.Metadata.Staff.Story = e.authors
+	if image, ok := e.image(); ok {
+		manga.Metadata.Cover.ExtraLarge = image.href
+	}
+
+	return manga, true
+}
+
+// searchURL returns the search address template of the catalog.
+// It is looked up in the root feed once, following the OpenSearch description if needed
+func (o *OPDS) searchURL(ctx context.Context) (string, error) {
+	o.mutex.Lock()
+	template := o.searchTemplate
+	o.mutex.Unlock()
+
+	if template != "" {
+		return template, nil
+	}
+
+	root, err := o.feed(ctx, o.root)
+	if err != nil {
+		return "", err
+	}
+
+	search, ok := root.find(relSearch)
+	if !ok {
+		return "", errors.New("catalog does not support search")
+	}
+
+	template = search.href
+	if search.mediaType == typeOpenSearch {
+		template, err = o.openSearchTemplate(ctx, search.href)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	o.mutex.Lock()
+	o.searchTemplate = template
+	o.mutex.Unlock()
+
+	return template, nil
+}
+
+type openSearchDescription struct {
+	URLs []struct {
+		Type     string `xml:"type,attr"`
+		Template string `xml:"template,attr"`
+	} `xml:"Url"`
+}
+
+// openSearchTemplate reads the search template of the catalog feeds from the OpenSearch description
+func (o *OPDS) openSearchTemplate(ctx context.Context, address string) (string, error) {
+	data, err := o.read(ctx, address)
+	if err != nil {
+		return "", err
+	}
+
+	var description openSearchDescription
+	if err = xml.Unmarshal(data, &description); err != nil {
+		return "", err
+	}
+
+	base, err := url.Parse(address)
+	if err != nil {
+		return "", err
+	}
+
+	for _, u := range description.URLs {
+		if strings.Contains(u.Type, "atom+xml") || strings.Contains(u.Type, "opds") {
+			return resolveHref(base, u.Template), nil
+		}
+	}
+
+	return "", errors.New("OpenSearch description has no catalog search template")
+}
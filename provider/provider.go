@@ -2,11 +2,16 @@ package provider
 
 import (
 	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/log"
 	"github.com/preetbiswas12/Kage/provider/custom"
+	"github.com/preetbiswas12/Kage/provider/declarative"
+	"github.com/preetbiswas12/Kage/provider/generic"
 	"github.com/preetbiswas12/Kage/source"
 	"github.com/preetbiswas12/Kage/util"
 	"github.com/preetbiswas12/Kage/where"
 	"github.com/samber/lo"
+	"github.com/spf13/viper"
 	"os"
 	"path/filepath"
 )
@@ -24,7 +29,17 @@ func (p Provider) String() string {
 }
 
 func Builtins() []*Provider {
-	return builtinProviders
+	// OPDS catalog is available only when it's configured
+	if viper.GetString(key.OPDSURL) == "" {
+		return builtinProviders
+	}
+
+	return append(builtinProviders[:len(builtinProviders):len(builtinProviders)], opdsProvider)
+}
+
+// Register adds the provider to the built-in ones
+func Register(provider *Provider) {
+	builtinProviders = append(builtinProviders, provider)
 }
 
 func Customs() []*Provider {
@@ -67,6 +82,48 @@ func Customs() []*Provider {
 		}
 	}
 
+	return append(providers, declaratives(files, providers)...)
+}
+
+// declaratives returns providers of the declarative scrapers among the files in the sources directory.
+// Scrapers share the ID and the name with the Lua ones of the same file stem,
+// so the files whose stem is already taken are skipped
+func declaratives(files []os.FileInfo, taken []*Provider) []*Provider {
+	paths := lo.FilterMap(files, func(f os.FileInfo, _ int) (string, bool) {
+		if lo.Contains(declarative.Extensions, filepath.Ext(f.Name())) {
+			return filepath.Join(where.Sources(), f.Name()), true
+		}
+		return "", false
+	})
+	names := lo.SliceToMap(taken, func(p *Provider) (string, bool) {
+		return p.Name, true
+	})
+	providers := make([]*Provider, 0, len(paths))
+
+	for _, path := range paths {
+		name := util.FileStem(path)
+		if names[name] {
+			log.Warnf("skipping %s: scraper %q is already defined", filepath.Base(path), name)
+			continue
+		}
+
+		names[name] = true
+		path := path
+		providers = append(providers, &Provider{
+			ID:       custom.IDfromName(name),
+			IsCustom: true,
+			Name:     name,
+			CreateSource: func() (source.Source, error) {
+				conf, err := declarative.Load(path)
+				if err != nil {
+					return nil, err
+				}
+
+				return generic.New(conf), nil
+			},
+		})
+	}
+
 	return providers
 }
 
@@ -0,0 +1,194 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	levenshtein "github.com/ka-weihe/fast-levenshtein"
+	"github.com/samber/lo"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// similarity is the maximum share of the name length that may differ
+// for two mangas to be considered the same
+const similarity = 0.2
+
+// AggregatedManga is the same manga found in several sources.
+type AggregatedManga struct {
+	// Name of the most relevant variant
+	Name string
+	// Mangas are the variants of the manga, one per source, the most relevant first
+	Mangas []*Manga
+
+	distance int
+}
+
+// Sources returns names of the sources that have the manga
+func (a *AggregatedManga) Sources() []string {
+	return lo.Map(a.Mangas, func(manga *Manga, _ int) string {
+		return manga.Source.Name()
+	})
+}
+
+// normalizeTitle lowercases the title and removes punctuation and extra spaces
+func normalizeTitle(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// similar reports whether the normalized titles are close enough to be the same manga
+func similar(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	longest := lo.Max([]int{len([]rune(a)), len([]rune(b))})
+	return float64(levenshtein.Distance(a, b)) <= float64(longest)*similarity
+}
+
+// Aggregate groups the mangas with similar titles from different sources
+// and ranks the groups by relevance to the query.
+// Variants are ordered by relevance, then by the order of sources
+func Aggregate(query string, sources []Source, mangas []*Manga) []*AggregatedManga {
+	query = normalizeTitle(query)
+
+	type group struct {
+		*AggregatedManga
+		title string
+		order int
+	}
+
+	var (
+		groups   []*group
+		distance = make(map[*Manga]int, len(mangas))
+	)
+
+	for _, manga := range mangas {
+		title := normalizeTitle(manga.Name)
+		distance[manga] = levenshtein.Distance(query, title)
+
+		g, ok := lo.Find(groups, func(g *group) bool {
+			// mangas of the same source are always different
+			return similar(g.title, title) && !lo.ContainsBy(g.Mangas, func(m *Manga) bool {
+				return m.Source == manga.Source
+			})
+		})
+
+		if !ok {
+			g = &group{
+				AggregatedManga: &AggregatedManga{},
+				title:           title,
+				order:           len(groups),
+			}
+			groups = append(groups, g)
+		}
+
+		g.Mangas = append(g.Mangas, manga)
+	}
+
+	sourceIndex := func(manga *Manga) int {
+		return lo.IndexOf(sources, manga.Source)
+	}
+
+	for _, g := range groups {
+		sort.SliceStable(g.Mangas, func(i, j int) bool {
+			a, b := g.Mangas[i], g.Mangas[j]
+			if distance[a] != distance[b] {
+				return distance[a] < distance[b]
+			}
+
+			return sourceIndex(a) < sourceIndex(b)
+		})
+
+		g.Name = g.Mangas[0].Name
+		g.distance = distance[g.Mangas[0]]
+	}
+
+	// closest to the query first, then the ones found in more sources
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+
+		if len(a.Mangas) != len(b.Mangas) {
+			return len(a.Mangas) > len(b.Mangas)
+		}
+
+		return a.order < b.order
+	})
+
+	return lo.Map(groups, func(g *group, _ int) *AggregatedManga {
+		return g.AggregatedManga
+	})
+}
+
+// SearchError is the error of the source that failed to search
+type SearchError struct {
+	// Source is the name of the source
+	Source string
+	Err    error
+}
+
+func (e *SearchError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Source, e.Err)
+}
+
+func (e *SearchError) Unwrap() error {
+	return e.Err
+}
+
+// SearchAll loads the next page of results from all the iterators concurrently.
+// Each source is bounded by its own search timeout, so that a slow source does not hold back the others.
+// Sources that failed are reported by the errors of SearchError type, results of the rest are returned anyway
+func SearchAll(ctx context.Context, iterators []*SearchIterator) ([]*Manga, []error) {
+	var (
+		results = make([][]*Manga, len(iterators))
+		errs    = make([]error, 0)
+		mutex   = sync.Mutex{}
+		wg      = sync.WaitGroup{}
+	)
+
+	wg.Add(len(iterators))
+	for i, it := range iterators {
+		go func(i int, it *SearchIterator) {
+			defer wg.Done()
+
+			mangas, err := it.Next(ctx)
+			if err != nil {
+				mutex.Lock()
+				errs = append(errs, &SearchError{Source: it.Source().Name(), Err: err})
+				mutex.Unlock()
+				return
+			}
+
+			results[i] = mangas
+		}(i, it)
+	}
+
+	wg.Wait()
+
+	// keep the order of sources regardless of which one answered first
+	return lo.Flatten(results), errs
+}
+
+// FindSimilar returns the manga with the title closest to the given one.
+// Returns false if none of the titles is similar enough
+func FindSimilar(title string, mangas []*Manga) (*Manga, bool) {
+	title = normalizeTitle(title)
+
+	candidates := lo.Filter(mangas, func(manga *Manga, _ int) bool {
+		return similar(title, normalizeTitle(manga.Name))
+	})
+
+	if len(candidates) == 0 {
+		return nil, false
+	}
+
+	return lo.MinBy(candidates, func(a, b *Manga) bool {
+		return levenshtein.Distance(title, normalizeTitle(a.Name)) < levenshtein.Distance(title, normalizeTitle(b.Name))
+	}), true
+}
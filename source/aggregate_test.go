@@ -0,0 +1,110 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type testNamedSource struct {
+	testSource
+	name string
+}
+
+func (t testNamedSource) Name() string {
+	return t.name
+}
+
+type testFailingSource struct {
+	testNamedSource
+}
+
+func (t testFailingSource) Search(string) ([]*Manga, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestAggregate(t *testing.T) {
+	Convey("Given mangas found in several sources", t, func() {
+		first, second := testNamedSource{name: "First"}, testNamedSource{name: "Second"}
+		sources := []Source{first, second}
+
+		mangas := []*Manga{
+			{Name: "Boruto: Naruto Next Generations", Source: first},
+			{Name: "Naruto", Source: first},
+			{Name: "Naruto (Colored)", Source: first},
+			{Name: "NARUTO", Source: second},
+			{Name: "Naruto!", Source: second},
+		}
+
+		Convey("When they are aggregated", func() {
+			merged := Aggregate("naruto", sources, mangas)
+
+			Convey("The same titles from different sources should be merged", func() {
+				So(merged, ShouldHaveLength, 4)
+				So(merged[0].Sources(), ShouldResemble, []string{"First", "Second"})
+			})
+
+			Convey("Mangas of the same source should not be merged", func() {
+				So(lo.Map(merged, func(m *AggregatedManga, _ int) string {
+					return m.Name
+				}), ShouldResemble, []string{"Naruto", "Naruto!", "Naruto (Colored)", "Boruto: Naruto Next Generations"})
+			})
+		})
+	})
+}
+
+func TestFindSimilar(t *testing.T) {
+	Convey("Given mangas of a source", t, func() {
+		mangas := []*Manga{
+			{Name: "Naruto (Colored)"},
+			{Name: "NARUTO"},
+			{Name: "Boruto"},
+		}
+
+		Convey("The closest similar title should be found", func() {
+			manga, ok := FindSimilar("Naruto", mangas)
+			So(ok, ShouldBeTrue)
+			So(manga.Name, ShouldEqual, "NARUTO")
+		})
+
+		Convey("Different titles should not be found", func() {
+			_, ok := FindSimilar("One Piece", mangas)
+			So(ok, ShouldBeFalse)
+		})
+	})
+}
+
+func TestSearchAll(t *testing.T) {
+	Convey("Given iterators of several sources", t, func() {
+		iterators := []*SearchIterator{
+			NewSearchIterator(testNamedSource{name: "First"}, "query"),
+			NewSearchIterator(testNamedSource{name: "Second"}, "query"),
+		}
+
+		Convey("When searching with all of them", func() {
+			_, errs := SearchAll(context.Background(), iterators)
+
+			Convey("Each source should be searched", func() {
+				So(errs, ShouldBeEmpty)
+				So(lo.EveryBy(iterators, func(it *SearchIterator) bool {
+					return !it.HasNext()
+				}), ShouldBeTrue)
+			})
+		})
+
+		Convey("When one of them fails", func() {
+			iterators = append(iterators, NewSearchIterator(testFailingSource{testNamedSource{name: "Broken"}}, "query"))
+			_, errs := SearchAll(context.Background(), iterators)
+
+			Convey("The failed source should be named by the error", func() {
+				So(errs, ShouldHaveLength, 1)
+
+				var searchErr *SearchError
+				So(errors.As(errs[0], &searchErr), ShouldBeTrue)
+				So(searchErr.Source, ShouldEqual, "Broken")
+			})
+		})
+	})
+}
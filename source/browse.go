@@ -0,0 +1,57 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"github.com/samber/lo"
+)
+
+// BrowseList is a list of mangas that can be browsed without a query.
+type BrowseList string
+
+const (
+	// BrowseLatest lists mangas with the latest chapter updates
+	BrowseLatest BrowseList = "latest"
+	// BrowsePopular lists the most popular mangas
+	BrowsePopular BrowseList = "popular"
+	// BrowseRecent lists recently added mangas
+	BrowseRecent BrowseList = "recent"
+)
+
+// BrowseLists are all the known browse lists.
+var BrowseLists = []BrowseList{BrowseLatest, BrowsePopular, BrowseRecent}
+
+// Browsable is a Source that can list mangas without a query.
+type Browsable interface {
+	Source
+	// BrowseLists returns lists that the source supports
+	BrowseLists() []BrowseList
+	// BrowsePage returns mangas of the list on the page given by the token.
+	// Empty token stands for the first page.
+	BrowsePage(ctx context.Context, list BrowseList, token string) (*SearchPage, error)
+}
+
+// BrowseListsOf returns lists supported by the source.
+// Returns nil if the source can not be browsed.
+func BrowseListsOf(src Source) []BrowseList {
+	if b, ok := src.(Browsable); ok {
+		return b.BrowseLists()
+	}
+
+	return nil
+}
+
+// NewBrowseIterator creates an iterator over mangas of the browse list.
+func NewBrowseIterator(src Source, list BrowseList) (*SearchIterator, error) {
+	b, ok := src.(Browsable)
+	if !ok || !lo.Contains(b.BrowseLists(), list) {
+		return nil, fmt.Errorf("source %s does not support browsing %s mangas", src.Name(), list)
+	}
+
+	return &SearchIterator{
+		source: src,
+		page: func(ctx context.Context, token string) (*SearchPage, error) {
+			return b.BrowsePage(ctx, list, token)
+		},
+	}, nil
+}
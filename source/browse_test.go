@@ -0,0 +1,57 @@
+package source
+
+import (
+	"context"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type testBrowsableSource struct {
+	testSource
+}
+
+func (testBrowsableSource) BrowseLists() []BrowseList {
+	return []BrowseList{BrowseLatest}
+}
+
+func (testBrowsableSource) BrowsePage(_ context.Context, list BrowseList, _ string) (*SearchPage, error) {
+	return &SearchPage{Mangas: []*Manga{{Name: string(list)}}}, nil
+}
+
+func TestNewBrowseIterator(t *testing.T) {
+	Convey("Given a source that can not be browsed", t, func() {
+		Convey("When NewBrowseIterator is called", func() {
+			_, err := NewBrowseIterator(&testSource{}, BrowseLatest)
+
+			Convey("It should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+
+	Convey("Given a browsable source", t, func() {
+		src := testBrowsableSource{}
+
+		Convey("When unsupported list is browsed", func() {
+			_, err := NewBrowseIterator(src, BrowsePopular)
+
+			Convey("It should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When supported list is browsed", func() {
+			it, err := NewBrowseIterator(src, BrowseLatest)
+			So(err, ShouldBeNil)
+
+			mangas, err := it.Next(context.Background())
+
+			Convey("It should return mangas of the list", func() {
+				So(err, ShouldBeNil)
+				So(len(mangas), ShouldEqual, 1)
+				So(mangas[0].Name, ShouldEqual, string(BrowseLatest))
+				So(it.HasNext(), ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -1,8 +1,10 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"github.com/dustin/go-humanize"
+	"github.com/preetbiswas12/Kage/color"
 	"github.com/preetbiswas12/Kage/constant"
 	"github.com/preetbiswas12/Kage/filesystem"
 	"github.com/preetbiswas12/Kage/key"
@@ -25,12 +27,26 @@ type Chapter struct {
 	URL string `json:"url" jsonschema:"description=URL of the chapter"`
 	// Index of the chapter in the manga.
 	Index uint16 `json:"index" jsonschema:"description=Index of the chapter in the manga"`
+	// Number of the chapter as given by the source, e.g. "12.5". Empty if unknown.
+	Number string `json:"number" jsonschema:"description=Number of the chapter as given by the source"`
 	// ID of the chapter in the source.
 	ID string `json:"id" jsonschema:"description=ID of the chapter in the source"`
 	// Volume which the chapter belongs to.
 	Volume string `json:"volume" jsonschema:"description=Volume which the chapter belongs to"`
+	// Groups that translated the chapter.
+	Groups []string `json:"groups" jsonschema:"description=Scanlation groups that translated the chapter"`
+	// Language of the chapter translation as ISO 639-1 code.
+	Language string `json:"language" jsonschema:"description=Language of the chapter translation"`
+	// PagesCount is the number of pages reported by the source before the pages are fetched. Zero if unknown.
+	PagesCount int `json:"pagesCount" jsonschema:"description=Number of pages reported by the source"`
+	// Quality of the pages, if the source offers several. Empty otherwise.
+	Quality string `json:"quality" jsonschema:"description=Quality of the pages, if the source offers several"`
+	// PublishedAt is the date when the chapter was released. Zero if unknown.
+	PublishedAt time.Time `json:"publishedAt" jsonschema:"description=Date when the chapter was released"`
 	// Manga that the chapter belongs to.
 	Manga *Manga `json:"-"`
+	// Fallback is the same chapter of another source that the pages were downloaded from. Nil if none.
+	Fallback *Chapter `json:"-"`
 	// Pages of the chapter.
 	Pages []*Page `json:"pages" jsonschema:"description=Pages of the chapter"`
 
@@ -44,12 +60,23 @@ func (c *Chapter) String() string {
 
 // DownloadPages downloads the Pages contents of the Chapter.
 // Pages needs to be set before calling this function.
-func (c *Chapter) DownloadPages(temp bool, progress func(string)) (err error) {
+func (c *Chapter) DownloadPages(temp bool, progress func(string)) error {
+	return c.DownloadPagesContext(context.Background(), temp, progress)
+}
+
+// DownloadPagesContext is the same as DownloadPages,
+// but stops downloading remaining pages once the context is done.
+func (c *Chapter) DownloadPagesContext(ctx context.Context, temp bool, progress func(string)) (err error) {
 	c.size = 0
+	pages := util.Quantify(len(c.Pages), "page", "pages")
+	if c.Quality != "" {
+		pages += " in " + style.Fg(color.Yellow)(c.Quality)
+	}
+
 	status := func() string {
 		return fmt.Sprintf(
 			"Downloading %s %s",
-			util.Quantify(len(c.Pages), "page", "pages"),
+			pages,
 			style.Faint(c.SizeHuman()),
 		)
 	}
@@ -79,29 +106,45 @@ func (c *Chapter) DownloadPages(temp bool, progress func(string)) (err error) {
 			}
 
 			// Acquire semaphore
-			semaphore <- struct{}{}
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			}
 			defer func() { <-semaphore }() // Release semaphore
 
 			// Retry logic for page downloads
 			const maxRetries = 3
 			var downloadErr error
 			for retry := 0; retry <= maxRetries; retry++ {
-				downloadErr = page.Download()
+				downloadErr = page.DownloadContext(ctx)
 				if downloadErr == nil {
 					c.size += page.Size
 					progress(status())
 					return
 				}
-				
+
+				// Retrying makes no sense if the download was cancelled
+				if ctx.Err() != nil {
+					err = ctx.Err()
+					return
+				}
+
 				// If this was the last retry, set the error
 				if retry == maxRetries {
 					err = fmt.Errorf("failed to download page #%d after %d retries: %w", page.Index, maxRetries, downloadErr)
 					return
 				}
-				
+
 				// Exponential backoff: wait 2^retry seconds before retrying
 				waitTime := time.Duration(1<<uint(retry)) * time.Second
-				time.Sleep(waitTime)
+				select {
+				case <-time.After(waitTime):
+				case <-ctx.Done():
+					err = ctx.Err()
+					return
+				}
 			}
 		}
 
@@ -137,6 +180,7 @@ func (c *Chapter) formattedName() (name string) {
 		"chapter":        c.Name,
 		"index":          fmt.Sprintf("%d", c.Index),
 		"padded-index":   fmt.Sprintf("%04d", c.Index),
+		"chapter-number": c.NumberOrIndex(),
 		"chapters-count": fmt.Sprintf("%d", len(c.Manga.Chapters)),
 		"volume":         c.Volume,
 		"source":         sourceName,
@@ -165,6 +209,10 @@ func (c *Chapter) Filename() (filename string) {
 }
 
 func (c *Chapter) IsDownloaded() bool {
+	if _, ok := c.Source().(LocalSource); ok {
+		return true
+	}
+
 	if c.isDownloaded.IsPresent() {
 		return c.isDownloaded.MustGet()
 	}
@@ -188,7 +236,19 @@ func (c *Chapter) path(relativeTo string, createVolumeDir bool) (path string, er
 	return
 }
 
+// LocalSource is a Source of the chapters stored on the disk, such as the downloaded ones.
+// Its chapters are never downloaded, the file they are read from is the chapter itself
+type LocalSource interface {
+	Source
+	// ChapterPath returns the path of the chapter file
+	ChapterPath(chapter *Chapter) string
+}
+
 func (c *Chapter) Path(temp bool) (path string, err error) {
+	if local, ok := c.Source().(LocalSource); ok {
+		return local.ChapterPath(c), nil
+	}
+
 	var manga string
 	manga, err = c.Manga.Path(temp)
 	if err != nil {
@@ -202,6 +262,15 @@ func (c *Chapter) Source() Source {
 	return c.Manga.Source
 }
 
+// UseFallback takes the downloaded pages of the same chapter from another source.
+func (c *Chapter) UseFallback(fallback *Chapter) {
+	c.Fallback = fallback
+	c.Pages = fallback.Pages
+	c.Quality = fallback.Quality
+	c.size = fallback.size
+	c.isDownloaded = fallback.isDownloaded
+}
+
 func (c *Chapter) ComicInfo() *ComicInfo {
 	var (
 		day, month, year int
@@ -214,6 +283,10 @@ func (c *Chapter) ComicInfo() *ComicInfo {
 			day = t.Day()
 			month = int(t.Month())
 			year = t.Year()
+		} else if !c.PublishedAt.IsZero() {
+			day = c.PublishedAt.Day()
+			month = int(c.PublishedAt.Month())
+			year = c.PublishedAt.Year()
 		} else {
 			day = c.Manga.Metadata.StartDate.Day
 			month = c.Manga.Metadata.StartDate.Month
@@ -221,28 +294,43 @@ func (c *Chapter) ComicInfo() *ComicInfo {
 		}
 	} // empty dates will be omitted
 
+	notes := "Downloaded with Mangal. https://github.com/preetbiswas12/Kage"
+	if c.Quality != "" {
+		notes += fmt.Sprintf(" Quality: %s.", c.Quality)
+	}
+
+	if c.Fallback != nil {
+		notes += fmt.Sprintf(" Pages from %s: %s.", c.Fallback.Source().Name(), c.Fallback.URL)
+	}
+
+	translator := strings.Join(c.Manga.Metadata.Staff.Translation, ",")
+	if len(c.Groups) > 0 {
+		translator = strings.Join(c.Groups, ",")
+	}
+
 	return &ComicInfo{
 		XmlnsXsd: "http://www.w3.org/2001/XMLSchema",
 		XmlnsXsi: "http://www.w3.org/2001/XMLSchema-instance",
 
-		Title:      c.Name,
-		Series:     c.Manga.Name,
-		Number:     int(c.Index),
-		Web:        c.URL,
-		Genre:      strings.Join(c.Manga.Metadata.Genres, ","),
-		PageCount:  len(c.Pages),
-		Summary:    c.Manga.Metadata.Summary,
-		Count:      c.Manga.Metadata.Chapters,
-		Characters: strings.Join(c.Manga.Metadata.Characters, ","),
-		Year:       year,
-		Month:      month,
-		Day:        day,
-		Writer:     strings.Join(c.Manga.Metadata.Staff.Story, ","),
-		Penciller:  strings.Join(c.Manga.Metadata.Staff.Art, ","),
-		Letterer:   strings.Join(c.Manga.Metadata.Staff.Lettering, ","),
-		Translator: strings.Join(c.Manga.Metadata.Staff.Translation, ","),
-		Tags:       strings.Join(c.Manga.Metadata.Tags, ","),
-		Notes:      "Downloaded with Mangal. https://github.com/preetbiswas12/Kage",
-		Manga:      "YesAndRightToLeft",
+		Title:       c.Name,
+		Series:      c.Manga.Name,
+		Number:      c.NumberOrIndex(),
+		Web:         c.URL,
+		Genre:       strings.Join(c.Manga.Metadata.Genres, ","),
+		PageCount:   len(c.Pages),
+		Summary:     c.Manga.Metadata.Summary,
+		Count:       c.Manga.Metadata.Chapters,
+		Characters:  strings.Join(c.Manga.Metadata.Characters, ","),
+		Year:        year,
+		Month:       month,
+		Day:         day,
+		Writer:      strings.Join(c.Manga.Metadata.Staff.Story, ","),
+		Penciller:   strings.Join(c.Manga.Metadata.Staff.Art, ","),
+		Letterer:    strings.Join(c.Manga.Metadata.Staff.Lettering, ","),
+		Translator:  translator,
+		Tags:        strings.Join(c.Manga.Metadata.Tags, ","),
+		LanguageISO: c.Language,
+		Notes:       notes,
+		Manga:       "YesAndRightToLeft",
 	}
 }
@@ -9,6 +9,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/spf13/viper"
 	"testing"
+	"time"
 )
 
 func init() {
@@ -52,3 +53,58 @@ func TestChapter_ComicInfoXML(t *testing.T) {
 		})
 	})
 }
+
+func TestChapter_ComicInfoTranslation(t *testing.T) {
+	Convey("Given a chapter with groups, language, publish date and quality", t, func() {
+		chapter := testChapter
+		chapter.Groups = []string{"Group A", "Group B"}
+		chapter.Language = "en"
+		chapter.PublishedAt = time.Date(2022, time.March, 14, 0, 0, 0, 0, time.UTC)
+		chapter.Quality = "data-saver"
+
+		addDate := viper.GetBool(key.MetadataComicInfoXMLAddDate)
+		alternativeDate := viper.GetBool(key.MetadataComicInfoXMLAlternativeDate)
+		viper.Set(key.MetadataComicInfoXMLAddDate, true)
+		viper.Set(key.MetadataComicInfoXMLAlternativeDate, false)
+		defer viper.Set(key.MetadataComicInfoXMLAddDate, addDate)
+		defer viper.Set(key.MetadataComicInfoXMLAlternativeDate, alternativeDate)
+
+		Convey("When ComicInfo is called", func() {
+			info := chapter.ComicInfo()
+
+			Convey("It should contain the translation details", func() {
+				So(info.Translator, ShouldEqual, "Group A,Group B")
+				So(info.LanguageISO, ShouldEqual, "en")
+				So(info.Year, ShouldEqual, 2022)
+				So(info.Month, ShouldEqual, 3)
+				So(info.Day, ShouldEqual, 14)
+				So(info.Notes, ShouldContainSubstring, "Quality: data-saver")
+			})
+		})
+	})
+}
+
+func TestChapter_UseFallback(t *testing.T) {
+	Convey("Given a chapter and the same chapter of another source", t, func() {
+		chapter := testChapter
+		other := &Chapter{
+			Name:  "Chapter 1",
+			URL:   "https://other.example.com/1",
+			Pages: []*Page{{Index: 0}},
+			Manga: &Manga{Name: testManga.Name, Source: testNamedSource{name: "Other"}},
+		}
+
+		Convey("When the fallback is used", func() {
+			chapter.UseFallback(other)
+
+			Convey("The pages should be taken from the fallback", func() {
+				So(chapter.Pages, ShouldResemble, other.Pages)
+				So(chapter.Source().Name(), ShouldEqual, "test")
+			})
+
+			Convey("ComicInfo should record the source of the pages", func() {
+				So(chapter.ComicInfo().Notes, ShouldContainSubstring, "Pages from Other: https://other.example.com/1")
+			})
+		})
+	})
+}
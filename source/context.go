@@ -0,0 +1,104 @@
+package source
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/preetbiswas12/Kage/network"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// withTimeout bounds the context with the timeout (in seconds) stored under the given config key.
+// Non-positive timeouts leave the context without a deadline.
+func withTimeout(ctx context.Context, timeoutKey string) (context.Context, context.CancelFunc) {
+	seconds := viper.GetInt(timeoutKey)
+	if seconds <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, time.Duration(seconds)*time.Second)
+}
+
+// withSource marks the network requests made with the context as the ones of the source,
+// so that the network settings of the source apply to them
+func withSource(ctx context.Context, src Source) context.Context {
+	if src == nil {
+		return ctx
+	}
+
+	return network.WithSource(ctx, src.Name())
+}
+
+// await runs f and waits for its result unless the context is done first.
+// It is used for sources that do not implement ContextSource,
+// so that the caller is not blocked by them.
+func await[T any](ctx context.Context, f func() (T, error)) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := f()
+		done <- result{value, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Search for mangas with the given source.
+// The search is cancelled when the context is done or the configured timeout is exceeded.
+func Search(ctx context.Context, src Source, query string) ([]*Manga, error) {
+	ctx, cancel := withTimeout(withSource(ctx, src), key.NetworkSearchTimeout)
+	defer cancel()
+
+	if s, ok := src.(ContextSource); ok {
+		return s.SearchContext(ctx, query)
+	}
+
+	return await(ctx, func() ([]*Manga, error) {
+		return src.Search(query)
+	})
+}
+
+// ChaptersOf the manga with the given source.
+// The request is cancelled when the context is done or the configured timeout is exceeded.
+func ChaptersOf(ctx context.Context, src Source, manga *Manga) ([]*Chapter, error) {
+	ctx, cancel := withTimeout(withSource(ctx, src), key.NetworkChaptersTimeout)
+	defer cancel()
+
+	if s, ok := src.(ContextSource); ok {
+		return s.ChaptersOfContext(ctx, manga)
+	}
+
+	return await(ctx, func() ([]*Chapter, error) {
+		return src.ChaptersOf(manga)
+	})
+}
+
+// PagesOf the chapter with the given source.
+// The request is cancelled when the context is done or the configured timeout is exceeded.
+func PagesOf(ctx context.Context, src Source, chapter *Chapter) ([]*Page, error) {
+	ctx, cancel := withTimeout(withSource(ctx, src), key.NetworkPagesTimeout)
+	defer cancel()
+
+	if s, ok := src.(ContextSource); ok {
+		return s.PagesOfContext(ctx, chapter)
+	}
+
+	return await(ctx, func() ([]*Page, error) {
+		return src.PagesOf(chapter)
+	})
+}
@@ -0,0 +1,32 @@
+package source
+
+import (
+	"context"
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	Convey("Given a source without context support", t, func() {
+		src := &testSource{}
+
+		Convey("When Search is called with an active context", func() {
+			_, err := Search(context.Background(), src, "test")
+
+			Convey("It should not return an error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When Search is called with a cancelled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := Search(ctx, src, "test")
+
+			Convey("It should return the cancellation error", func() {
+				So(errors.Is(err, context.Canceled), ShouldBeTrue)
+			})
+		})
+	})
+}
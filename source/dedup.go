@@ -0,0 +1,152 @@
+package source
+
+import (
+	"fmt"
+	"github.com/preetbiswas12/Kage/key"
+	"github.com/samber/lo"
+	"github.com/spf13/viper"
+	"strings"
+)
+
+// Policies of collapsing chapters with the same number
+const (
+	// DedupNone keeps all the chapters
+	DedupNone = "none"
+	// DedupGroups prefers chapters of the preferred groups
+	DedupGroups = "groups"
+	// DedupPages prefers chapters with the highest page count
+	DedupPages = "pages"
+	// DedupNewest prefers the most recently published chapters
+	DedupNewest = "newest"
+)
+
+// DedupPolicies are all the available policies
+var DedupPolicies = []string{DedupNone, DedupGroups, DedupPages, DedupNewest}
+
+// DedupPolicy defines which chapter to keep among the chapters with the same number.
+type DedupPolicy struct {
+	// Prefer is the name of the policy
+	Prefer string
+	// Groups are the preferred groups, used by the DedupGroups policy
+	Groups []string
+}
+
+// ParseDedupPolicy parses the policy in the "policy[:group|group...]" form.
+// Groups default to the ones given
+func ParseDedupPolicy(description string, groups []string) (*DedupPolicy, error) {
+	prefer, groupsList, hasGroups := strings.Cut(strings.TrimSpace(description), ":")
+	prefer = strings.ToLower(strings.TrimSpace(prefer))
+
+	if prefer == "" {
+		prefer = DedupNone
+	}
+
+	if !lo.Contains(DedupPolicies, prefer) {
+		return nil, fmt.Errorf("unknown duplicate chapters policy %q, expected one of %s", prefer, strings.Join(DedupPolicies, ", "))
+	}
+
+	if hasGroups {
+		groups = lo.Map(strings.Split(groupsList, "|"), func(group string, _ int) string {
+			return strings.TrimSpace(group)
+		})
+	}
+
+	return &DedupPolicy{Prefer: prefer, Groups: groups}, nil
+}
+
+// DedupPolicyFor returns the policy of the manga from the config.
+// Falls back to the global policy if the manga has none
+func DedupPolicyFor(manga *Manga) (*DedupPolicy, error) {
+	groups := viper.GetStringSlice(key.ChaptersPreferredGroups)
+
+	for _, entry := range viper.GetStringSlice(key.ChaptersMangaDedupPolicy) {
+		name, policy, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid manga policy %q, expected manga name=policy", entry)
+		}
+
+		if strings.EqualFold(strings.TrimSpace(name), manga.Name) {
+			return ParseDedupPolicy(policy, groups)
+		}
+	}
+
+	return ParseDedupPolicy(viper.GetString(key.ChaptersDedupPolicy), groups)
+}
+
+// Apply collapses chapters with the same number and language into the preferred one.
+// Chapters without numbers are always kept. Order and indexes of the chapters are preserved,
+// since they are shared with the source caches and used in the downloaded file names
+func (p *DedupPolicy) Apply(chapters []*Chapter) []*Chapter {
+	if p.Prefer == DedupNone {
+		return chapters
+	}
+
+	var (
+		deduped = make([]*Chapter, 0, len(chapters))
+		// position of the kept chapter in the deduped slice
+		kept = make(map[string]int)
+	)
+
+	for _, chapter := range chapters {
+		if chapter.Number == "" {
+			deduped = append(deduped, chapter)
+			continue
+		}
+
+		id := chapter.Language + "#" + chapter.Number
+		if i, ok := kept[id]; ok {
+			if p.better(chapter, deduped[i]) {
+				deduped[i] = chapter
+			}
+
+			continue
+		}
+
+		kept[id] = len(deduped)
+		deduped = append(deduped, chapter)
+	}
+
+	return deduped
+}
+
+// better reports whether the candidate is preferred over the current chapter.
+// On ties the current chapter wins, so the first one found is kept
+func (p *DedupPolicy) better(candidate, current *Chapter) bool {
+	switch p.Prefer {
+	case DedupGroups:
+		return p.groupRank(candidate) < p.groupRank(current)
+	case DedupPages:
+		return candidate.PagesCount > current.PagesCount
+	case DedupNewest:
+		return candidate.PublishedAt.After(current.PublishedAt)
+	default:
+		return false
+	}
+}
+
+// groupRank is the position of the first chapter group in the preferred groups.
+// Chapters of not preferred groups have the lowest rank
+func (p *DedupPolicy) groupRank(chapter *Chapter) int {
+	for i, preferred := range p.Groups {
+		for _, group := range chapter.Groups {
+			if strings.EqualFold(group, preferred) {
+				return i
+			}
+		}
+	}
+
+	return len(p.Groups)
+}
+
+// DedupChapters applies the duplicate chapters policy of the manga to the chapters.
+// Manga chapters are replaced with the result
+func DedupChapters(manga *Manga, chapters []*Chapter) ([]*Chapter, error) {
+	policy, err := DedupPolicyFor(manga)
+	if err != nil {
+		return nil, err
+	}
+
+	chapters = policy.Apply(chapters)
+	manga.Chapters = chapters
+	return chapters, nil
+}
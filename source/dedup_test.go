@@ -0,0 +1,114 @@
+package source
+
+import (
+	"github.com/preetbiswas12/Kage/key"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"testing"
+	"time"
+)
+
+func duplicateChapters() []*Chapter {
+	return []*Chapter{
+		{Name: "1a", Index: 1, Number: "1", Groups: []string{"Alpha"}, PagesCount: 20, PublishedAt: time.Unix(100, 0)},
+		{Name: "1b", Index: 2, Number: "1", Groups: []string{"Beta"}, PagesCount: 25, PublishedAt: time.Unix(300, 0)},
+		{Name: "1c", Index: 3, Number: "1", Groups: []string{"Gamma"}, PagesCount: 22, PublishedAt: time.Unix(200, 0)},
+		{Name: "extra", Index: 4},
+		{Name: "2a", Index: 5, Number: "2", Groups: []string{"Alpha"}},
+		{Name: "2fr", Index: 6, Number: "2", Language: "fr"},
+	}
+}
+
+func names(chapters []*Chapter) []string {
+	result := make([]string, len(chapters))
+	for i, chapter := range chapters {
+		result[i] = chapter.Name
+	}
+
+	return result
+}
+
+func TestDedupPolicy_Apply(t *testing.T) {
+	Convey("Given chapters with the same numbers", t, func() {
+		Convey("When none policy is applied", func() {
+			policy := mustPolicy(ParseDedupPolicy(DedupNone, nil))
+
+			Convey("All chapters should be kept", func() {
+				So(policy.Apply(duplicateChapters()), ShouldHaveLength, 6)
+			})
+		})
+
+		Convey("When groups policy is applied", func() {
+			policy := mustPolicy(ParseDedupPolicy("groups:gamma|beta", nil))
+
+			Convey("Chapters of the first preferred group should be kept", func() {
+				So(names(policy.Apply(duplicateChapters())), ShouldResemble, []string{"1c", "extra", "2a", "2fr"})
+			})
+		})
+
+		Convey("When pages policy is applied", func() {
+			policy := mustPolicy(ParseDedupPolicy(DedupPages, nil))
+
+			Convey("Chapters with most pages should be kept", func() {
+				So(names(policy.Apply(duplicateChapters())), ShouldResemble, []string{"1b", "extra", "2a", "2fr"})
+			})
+		})
+
+		Convey("When newest policy is applied", func() {
+			policy := mustPolicy(ParseDedupPolicy(DedupNewest, nil))
+			chapters := policy.Apply(duplicateChapters())
+
+			Convey("The newest chapters should be kept with their indexes", func() {
+				So(names(chapters), ShouldResemble, []string{"1b", "extra", "2a", "2fr"})
+				So(chapters[0].Index, ShouldEqual, 2)
+				So(chapters[3].Index, ShouldEqual, 6)
+			})
+		})
+	})
+}
+
+func TestDedupPolicyFor(t *testing.T) {
+	Convey("Given per manga policies", t, func() {
+		defer viper.Set(key.ChaptersMangaDedupPolicy, []string{})
+		defer viper.Set(key.ChaptersDedupPolicy, DedupNone)
+
+		viper.Set(key.ChaptersDedupPolicy, DedupNewest)
+		viper.Set(key.ChaptersMangaDedupPolicy, []string{"One Piece=groups:TCB Scans"})
+
+		Convey("When the manga has a policy", func() {
+			policy, err := DedupPolicyFor(&Manga{Name: "one piece"})
+
+			Convey("It should be used", func() {
+				So(err, ShouldBeNil)
+				So(policy.Prefer, ShouldEqual, DedupGroups)
+				So(policy.Groups, ShouldResemble, []string{"TCB Scans"})
+			})
+		})
+
+		Convey("When the manga has no policy", func() {
+			policy, err := DedupPolicyFor(&Manga{Name: "Berserk"})
+
+			Convey("The global policy should be used", func() {
+				So(err, ShouldBeNil)
+				So(policy.Prefer, ShouldEqual, DedupNewest)
+			})
+		})
+
+		Convey("When the policy is unknown", func() {
+			_, err := ParseDedupPolicy("random", nil)
+
+			Convey("It should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+// mustPolicy returns the policy, panicking on error
+func mustPolicy(policy *DedupPolicy, err error) *DedupPolicy {
+	if err != nil {
+		panic(err)
+	}
+
+	return policy
+}
@@ -0,0 +1,20 @@
+package source
+
+import "context"
+
+// Manga statuses, same as the ones used by Anilist
+const (
+	StatusFinished       = "FINISHED"
+	StatusReleasing      = "RELEASING"
+	StatusNotYetReleased = "NOT YET RELEASED"
+	StatusCancelled      = "CANCELLED"
+	StatusHiatus         = "HIATUS"
+)
+
+// DetailsProvider is a Source that provides details of its mangas,
+// such as authors, tags, status, description and cover.
+type DetailsProvider interface {
+	Source
+	// MangaDetails fills the metadata of the manga
+	MangaDetails(ctx context.Context, manga *Manga) error
+}
@@ -0,0 +1,63 @@
+package source
+
+import (
+	"context"
+	"github.com/preetbiswas12/Kage/key"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+	"testing"
+)
+
+type testDetailsSource struct {
+	testSource
+}
+
+func (testDetailsSource) MangaDetails(_ context.Context, manga *Manga) error {
+	manga.Metadata.Summary = "summary"
+	manga.Metadata.Status = StatusReleasing
+	manga.Metadata.Staff.Story = []string{"author"}
+	return nil
+}
+
+// blockingDetailsSource fetches the details until the context is done
+type blockingDetailsSource struct {
+	testSource
+}
+
+func (blockingDetailsSource) MangaDetails(ctx context.Context, _ *Manga) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestManga_PopulateMetadataFromSource(t *testing.T) {
+	Convey("Given a manga from a source that provides details", t, func() {
+		fetchAnilist := viper.GetBool(key.MetadataFetchAnilist)
+		viper.Set(key.MetadataFetchAnilist, false)
+		defer viper.Set(key.MetadataFetchAnilist, fetchAnilist)
+
+		manga := &Manga{Name: "test", Source: testDetailsSource{}}
+
+		Convey("When PopulateMetadata is called with anilist disabled", func() {
+			err := manga.PopulateMetadata(func(string) {})
+
+			Convey("It should fill the metadata from the source", func() {
+				So(err, ShouldBeNil)
+				So(manga.Metadata.Summary, ShouldEqual, "summary")
+				So(manga.Metadata.Status, ShouldEqual, StatusReleasing)
+				So(manga.Metadata.Staff.Story, ShouldResemble, []string{"author"})
+			})
+		})
+
+		Convey("When PopulateMetadataContext is called with a cancelled context", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			manga.Source = blockingDetailsSource{}
+			err := manga.PopulateMetadataContext(ctx, func(string) {})
+
+			Convey("It should stop fetching the details", func() {
+				So(err, ShouldEqual, context.Canceled)
+			})
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"github.com/samber/lo"
+	"net/url"
+	"strings"
+)
+
+// Common filter keys. Sources are encouraged to use them
+// so that the same filters can be applied to different sources.
+const (
+	FilterTags          = "tags"
+	FilterStatus        = "status"
+	FilterDemographic   = "demographic"
+	FilterContentRating = "content_rating"
+	FilterLanguage      = "language"
+	FilterYear          = "year"
+)
+
+// FilterOption is a value that a filter can take.
+type FilterOption struct {
+	// Name of the option shown to the user
+	Name string `json:"name"`
+	// Value of the option passed to the source
+	Value string `json:"value"`
+}
+
+// Filter describes a search filter supported by a source.
+type Filter struct {
+	// Key of the filter, e.g. "status"
+	Key string `json:"key"`
+	// Name of the filter shown to the user
+	Name string `json:"name"`
+	// Multiple is true if more than one value can be chosen
+	Multiple bool `json:"multiple"`
+	// Options the filter can take.
+	// Any value is accepted if empty
+	Options []FilterOption `json:"options"`
+}
+
+// Filters are values chosen for the search filters, mapped by filter keys.
+type Filters map[string][]string
+
+// Add a value for the filter key.
+func (f Filters) Add(key, value string) {
+	if !lo.Contains(f[key], value) {
+		f[key] = append(f[key], value)
+	}
+}
+
+// Get the first value of the filter key.
+func (f Filters) Get(key string) string {
+	if values := f[key]; len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Encode filters in a stable form, suitable for cache keys.
+func (f Filters) Encode() string {
+	return url.Values(f).Encode()
+}
+
+// For returns only the filters that are described by the given definitions.
+func (f Filters) For(definitions []*Filter) Filters {
+	filtered := make(Filters)
+
+	for _, definition := range definitions {
+		for _, value := range f[definition.Key] {
+			if len(definition.Options) > 0 && !lo.ContainsBy(definition.Options, func(option FilterOption) bool {
+				return option.Value == value
+			}) {
+				continue
+			}
+
+			filtered.Add(definition.Key, value)
+		}
+	}
+
+	return filtered
+}
+
+// Resolve validates filters against the given definitions.
+// Options may be referred to by their names, which are replaced with the values.
+func (f Filters) Resolve(definitions []*Filter) (Filters, error) {
+	resolved := make(Filters)
+
+	for k, values := range f {
+		definition, ok := lo.Find(definitions, func(definition *Filter) bool {
+			return definition.Key == k
+		})
+
+		if !ok {
+			keys := lo.Map(definitions, func(definition *Filter, _ int) string {
+				return definition.Key
+			})
+
+			return nil, fmt.Errorf("unknown filter %q, supported filters: %s", k, strings.Join(keys, ", "))
+		}
+
+		if !definition.Multiple && len(values) > 1 {
+			return nil, fmt.Errorf("filter %q accepts only one value", k)
+		}
+
+		for _, value := range values {
+			if len(definition.Options) == 0 {
+				resolved.Add(k, value)
+				continue
+			}
+
+			option, ok := lo.Find(definition.Options, func(option FilterOption) bool {
+				return option.Value == value || strings.EqualFold(option.Name, value)
+			})
+
+			if !ok {
+				return nil, fmt.Errorf("invalid value %q for filter %q", value, k)
+			}
+
+			resolved.Add(k, option.Value)
+		}
+	}
+
+	return resolved, nil
+}
+
+// Filterable is a Source that supports structured search filters.
+type Filterable interface {
+	Source
+	// SearchFilters returns the filters that the source supports
+	SearchFilters() ([]*Filter, error)
+	// SearchFilteredPage searches for mangas matching the filters on the page given by the token.
+	// Empty token stands for the first page.
+	SearchFilteredPage(ctx context.Context, query string, filters Filters, token string) (*SearchPage, error)
+}
+
+// FiltersOf returns the filters supported by the source.
+// Returns nil if the source does not support filters.
+func FiltersOf(src Source) ([]*Filter, error) {
+	if f, ok := src.(Filterable); ok {
+		return f.SearchFilters()
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,78 @@
+package source
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+var testFilters = []*Filter{
+	{
+		Key:      FilterStatus,
+		Name:     "Status",
+		Multiple: false,
+		Options:  []FilterOption{{Name: "Ongoing", Value: "ongoing"}, {Name: "Completed", Value: "completed"}},
+	},
+	{
+		Key:      FilterTags,
+		Name:     "Tags",
+		Multiple: true,
+		Options:  []FilterOption{{Name: "Action", Value: "1"}, {Name: "Comedy", Value: "2"}},
+	},
+	{
+		Key:  FilterYear,
+		Name: "Year",
+	},
+}
+
+func TestFilters_Resolve(t *testing.T) {
+	Convey("Given filter definitions", t, func() {
+		Convey("When valid filters are resolved", func() {
+			filters := Filters{FilterTags: {"action", "2"}, FilterYear: {"2001"}}
+			resolved, err := filters.Resolve(testFilters)
+
+			Convey("It should replace option names with values", func() {
+				So(err, ShouldBeNil)
+				So(resolved[FilterTags], ShouldResemble, []string{"1", "2"})
+				So(resolved.Get(FilterYear), ShouldEqual, "2001")
+			})
+		})
+
+		Convey("When unknown filter is resolved", func() {
+			_, err := Filters{"unknown": {"value"}}.Resolve(testFilters)
+
+			Convey("It should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When multiple values are given for a single value filter", func() {
+			_, err := Filters{FilterStatus: {"ongoing", "completed"}}.Resolve(testFilters)
+
+			Convey("It should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When invalid option is given", func() {
+			_, err := Filters{FilterStatus: {"hiatus"}}.Resolve(testFilters)
+
+			Convey("It should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestFilters_For(t *testing.T) {
+	Convey("Given filters", t, func() {
+		filters := Filters{FilterStatus: {"ongoing"}, FilterLanguage: {"en"}}
+
+		Convey("When For is called with definitions", func() {
+			filtered := filters.For(testFilters)
+
+			Convey("It should keep only the supported filters", func() {
+				So(filtered, ShouldResemble, Filters{FilterStatus: {"ongoing"}})
+			})
+		})
+	})
+}
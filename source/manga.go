@@ -1,11 +1,13 @@
 package source
 
 import (
+	"context"
 	"fmt"
 	"github.com/preetbiswas12/Kage/anilist"
 	"github.com/preetbiswas12/Kage/filesystem"
 	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/log"
+	"github.com/preetbiswas12/Kage/network"
 	"github.com/preetbiswas12/Kage/util"
 	"github.com/preetbiswas12/Kage/where"
 	"github.com/samber/lo"
@@ -143,6 +145,12 @@ func (m *Manga) GetCover() (string, error) {
 }
 
 func (m *Manga) DownloadCover(overwrite bool, path string, progress func(string)) error {
+	return m.DownloadCoverContext(context.Background(), overwrite, path, progress)
+}
+
+// DownloadCoverContext downloads the cover on behalf of the manga source.
+// The download is cancelled when the context is done
+func (m *Manga) DownloadCoverContext(ctx context.Context, overwrite bool, path string, progress func(string)) error {
 	if m.coverDownloaded {
 		return nil
 	}
@@ -177,7 +185,13 @@ func (m *Manga) DownloadCover(overwrite bool, path string, progress func(string)
 		}
 	}
 
-	resp, err := http.Get(cover)
+	req, err := http.NewRequestWithContext(withSource(ctx, m.Source), http.MethodGet, cover, nil)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+
+	resp, err := network.Client.Do(req)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -223,12 +237,39 @@ func (m *Manga) BindWithAnilist() error {
 	return nil
 }
 
+// PopulateMetadata fills the metadata with the details provided by the source.
+// Anilist metadata is merged on top of them if fetching from Anilist is enabled.
 func (m *Manga) PopulateMetadata(progress func(string)) error {
+	return m.PopulateMetadataContext(context.Background(), progress)
+}
+
+// PopulateMetadataContext is the same as PopulateMetadata,
+// but fetching the details from the source is stopped once the context is done.
+func (m *Manga) PopulateMetadataContext(ctx context.Context, progress func(string)) error {
 	if m.populated {
 		return nil
 	}
 	m.populated = true
 
+	var err error
+	if provider, ok := m.Source.(DetailsProvider); ok {
+		progress(fmt.Sprintf("Fetching details from %s", m.Source.Name()))
+		log.Infof("Populating details for %s from %s", m.Name, m.Source.Name())
+		if err = provider.MangaDetails(withSource(ctx, m.Source), m); err != nil {
+			log.Warn(err)
+		}
+	}
+
+	if !viper.GetBool(key.MetadataFetchAnilist) {
+		return err
+	}
+
+	return m.PopulateAnilistMetadata(progress)
+}
+
+// PopulateAnilistMetadata fills the metadata from the closest Anilist manga.
+// Fields that Anilist does not know about are left untouched.
+func (m *Manga) PopulateAnilistMetadata(progress func(string)) error {
 	progress("Fetching metadata from anilist")
 	log.Infof("Populating metadata for %s", m.Name)
 	if err := m.BindWithAnilist(); err != nil {
@@ -241,9 +282,30 @@ func (m *Manga) PopulateMetadata(progress func(string)) error {
 		return fmt.Errorf("manga '%s' not found on Anilist", m.Name)
 	}
 
-	m.Metadata.Genres = manga.Genres
+	// override takes the anilist value unless it is empty
+	override := func(value, anilistValue string) string {
+		if anilistValue != "" {
+			return anilistValue
+		}
+
+		return value
+	}
+
+	overrideSlice := func(value, anilistValue []string) []string {
+		if len(anilistValue) > 0 {
+			return anilistValue
+		}
+
+		if value == nil {
+			return make([]string, 0)
+		}
+
+		return value
+	}
+
+	m.Metadata.Genres = overrideSlice(m.Metadata.Genres, manga.Genres)
 	// replace <br> with newlines and remove other html tags
-	m.Metadata.Summary = regexp.
+	m.Metadata.Summary = override(m.Metadata.Summary, regexp.
 		MustCompile("<.*?>").
 		ReplaceAllString(
 			strings.
@@ -253,13 +315,13 @@ func (m *Manga) PopulateMetadata(progress func(string)) error {
 					"\n",
 				),
 			"",
-		)
+		))
 
 	var characters = make([]string, len(manga.Characters.Nodes))
 	for i, character := range manga.Characters.Nodes {
 		characters[i] = character.Name.Full
 	}
-	m.Metadata.Characters = characters
+	m.Metadata.Characters = overrideSlice(m.Metadata.Characters, characters)
 
 	var tags = make([]string, 0)
 	for _, tag := range manga.Tags {
@@ -267,42 +329,52 @@ func (m *Manga) PopulateMetadata(progress func(string)) error {
 			tags = append(tags, tag.Name)
 		}
 	}
-	m.Metadata.Tags = tags
+	m.Metadata.Tags = overrideSlice(m.Metadata.Tags, tags)
 
-	m.Metadata.Cover.ExtraLarge = manga.CoverImage.ExtraLarge
-	m.Metadata.Cover.Large = manga.CoverImage.Large
-	m.Metadata.Cover.Medium = manga.CoverImage.Medium
-	m.Metadata.Cover.Color = manga.CoverImage.Color
+	if manga.CoverImage.ExtraLarge != "" || manga.CoverImage.Large != "" || manga.CoverImage.Medium != "" {
+		m.Metadata.Cover.ExtraLarge = manga.CoverImage.ExtraLarge
+		m.Metadata.Cover.Large = manga.CoverImage.Large
+		m.Metadata.Cover.Medium = manga.CoverImage.Medium
+		m.Metadata.Cover.Color = manga.CoverImage.Color
+	}
 
-	m.Metadata.BannerImage = manga.BannerImage
+	m.Metadata.BannerImage = override(m.Metadata.BannerImage, manga.BannerImage)
 
-	m.Metadata.StartDate = date(manga.StartDate)
-	m.Metadata.EndDate = date(manga.EndDate)
+	if manga.StartDate.Year != 0 {
+		m.Metadata.StartDate = date(manga.StartDate)
+	}
 
-	m.Metadata.Status = strings.ReplaceAll(manga.Status, "_", " ")
-	m.Metadata.Synonyms = manga.Synonyms
+	if manga.EndDate.Year != 0 {
+		m.Metadata.EndDate = date(manga.EndDate)
+	}
 
-	m.Metadata.Staff.Story = make([]string, 0)
-	m.Metadata.Staff.Art = make([]string, 0)
-	m.Metadata.Staff.Translation = make([]string, 0)
-	m.Metadata.Staff.Lettering = make([]string, 0)
+	m.Metadata.Status = override(m.Metadata.Status, strings.ReplaceAll(manga.Status, "_", " "))
+	m.Metadata.Synonyms = overrideSlice(m.Metadata.Synonyms, manga.Synonyms)
 
-	m.Metadata.Chapters = manga.Chapters
+	if manga.Chapters != 0 {
+		m.Metadata.Chapters = manga.Chapters
+	}
 
+	var story, art, translation, lettering []string
 	for _, staff := range manga.Staff.Edges {
 		role := strings.ToLower(staff.Role)
 		switch {
 		case strings.Contains(role, "story"):
-			m.Metadata.Staff.Story = append(m.Metadata.Staff.Story, staff.Node.Name.Full)
+			story = append(story, staff.Node.Name.Full)
 		case strings.Contains(role, "art"):
-			m.Metadata.Staff.Art = append(m.Metadata.Staff.Art, staff.Node.Name.Full)
+			art = append(art, staff.Node.Name.Full)
 		case strings.Contains(role, "translator"):
-			m.Metadata.Staff.Translation = append(m.Metadata.Staff.Translation, staff.Node.Name.Full)
+			translation = append(translation, staff.Node.Name.Full)
 		case strings.Contains(role, "lettering"):
-			m.Metadata.Staff.Lettering = append(m.Metadata.Staff.Lettering, staff.Node.Name.Full)
+			lettering = append(lettering, staff.Node.Name.Full)
 		}
 	}
 
+	m.Metadata.Staff.Story = overrideSlice(m.Metadata.Staff.Story, story)
+	m.Metadata.Staff.Art = overrideSlice(m.Metadata.Staff.Art, art)
+	m.Metadata.Staff.Translation = overrideSlice(m.Metadata.Staff.Translation, translation)
+	m.Metadata.Staff.Lettering = overrideSlice(m.Metadata.Staff.Lettering, lettering)
+
 	// Anilist & Myanimelist + external
 	urls := make([]string, 2+len(manga.External))
 	urls[0] = manga.SiteURL
@@ -315,7 +387,7 @@ func (m *Manga) PopulateMetadata(progress func(string)) error {
 	})
 
 	urls = append(urls, fmt.Sprintf("https://myanimelist.net/manga/%d", manga.IDMal))
-	m.Metadata.URLs = urls
+	m.Metadata.URLs = lo.Uniq(append(urls, m.Metadata.URLs...))
 
 	return nil
 }
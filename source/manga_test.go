@@ -2,9 +2,11 @@ package source
 
 import (
 	"github.com/preetbiswas12/Kage/filesystem"
+	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/util"
 	"github.com/samber/lo"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
 	"os"
 	"path/filepath"
 	"testing"
@@ -96,6 +98,10 @@ func TestManga_Path(t *testing.T) {
 
 func TestManga_PopulateMetadata(t *testing.T) {
 	Convey("Given a manga", t, func() {
+		fetchAnilist := viper.GetBool(key.MetadataFetchAnilist)
+		viper.Set(key.MetadataFetchAnilist, true)
+		defer viper.Set(key.MetadataFetchAnilist, fetchAnilist)
+
 		Convey("When PopulateMetadata is called", func() {
 			err := testManga.PopulateMetadata(func(string) {})
 			Convey("It should not return an error", func() {
@@ -0,0 +1,95 @@
+package source
+
+import (
+	"golang.org/x/exp/slices"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// chapterNumberRegex matches numbers prefixed with "chapter", "ch." or "#"
+	chapterNumberRegex = regexp.MustCompile(`(?i)(?:chapter|chap\.?|ch\.?|#)\s*(\d+(?:[.,]\d+)?)`)
+	// anyNumberRegex matches the first number in the name
+	anyNumberRegex = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+	// volumeRegex matches volume prefixes, so that their numbers are not taken as chapter numbers
+	volumeRegex = regexp.MustCompile(`(?i)(?:volume|vol\.?|v\.)\s*\d+(?:[.,]\d+)?`)
+)
+
+// ParseChapterNumber extracts the chapter number from the chapter name.
+// Numbers prefixed with "chapter" or "ch." are preferred, otherwise the first number
+// not belonging to the volume is used.
+// Returns an empty string if the name contains no number
+func ParseChapterNumber(name string) string {
+	if groups := chapterNumberRegex.FindStringSubmatch(name); groups != nil {
+		return NormalizeChapterNumber(groups[1])
+	}
+
+	return NormalizeChapterNumber(anyNumberRegex.FindString(volumeRegex.ReplaceAllString(name, "")))
+}
+
+// NormalizeChapterNumber converts the chapter number to its canonical form,
+// e.g. "012.50" becomes "12.5".
+// Returns an empty string if the number is not valid
+func NormalizeChapterNumber(number string) string {
+	number = strings.ReplaceAll(strings.TrimSpace(number), ",", ".")
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// NumberOrIndex returns the chapter number, or the index if the number is unknown.
+func (c *Chapter) NumberOrIndex() string {
+	if c.Number != "" {
+		return c.Number
+	}
+
+	return strconv.Itoa(int(c.Index))
+}
+
+// float returns the chapter number as a float and whether it is known
+func (c *Chapter) float() (float64, bool) {
+	if c.Number == "" {
+		return 0, false
+	}
+
+	n, err := strconv.ParseFloat(c.Number, 64)
+	return n, err == nil
+}
+
+// CompareChapters compares chapters by their numbers, chapters with equal numbers are compared by index.
+// Chapters with unknown numbers go after the numbered ones in the order of their indexes,
+// so that the order is total even when numbered and unnumbered chapters are mixed
+func CompareChapters(a, b *Chapter) int {
+	an, aok := a.float()
+	bn, bok := b.float()
+
+	switch {
+	case aok && !bok:
+		return -1
+	case !aok && bok:
+		return 1
+	case aok && bok && an < bn:
+		return -1
+	case aok && bok && an > bn:
+		return 1
+	}
+
+	if a.Index < b.Index {
+		return -1
+	} else if a.Index > b.Index {
+		return 1
+	}
+
+	return 0
+}
+
+// SortChapters sorts chapters in ascending order of their numbers.
+// The sort is stable, so chapters with the same number keep their order
+func SortChapters(chapters []*Chapter) {
+	slices.SortStableFunc(chapters, CompareChapters)
+}
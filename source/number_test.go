@@ -0,0 +1,91 @@
+package source
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestParseChapterNumber(t *testing.T) {
+	Convey("Given chapter names", t, func() {
+		cases := map[string]string{
+			"Chapter 12":              "12",
+			"Chapter 12.5 - Extra":    "12.5",
+			"Vol.3 Ch.041":            "41",
+			"Volume 2 Episode 7":      "7",
+			"#105: The End":           "105",
+			"Ch. 3,5":                 "3.5",
+			"The Prologue":            "",
+			"Chapter 001.50 of 2 000": "1.5",
+		}
+
+		for name, number := range cases {
+			Convey("When parsing "+name, func() {
+				So(ParseChapterNumber(name), ShouldEqual, number)
+			})
+		}
+	})
+}
+
+func TestSortChapters(t *testing.T) {
+	Convey("Given chapters with numbers", t, func() {
+		chapters := []*Chapter{
+			{Name: "c", Number: "10", Index: 1},
+			{Name: "b", Number: "2.5", Index: 2},
+			{Name: "a", Number: "2", Index: 3},
+			{Name: "d", Index: 4},
+		}
+
+		Convey("When SortChapters is called", func() {
+			SortChapters(chapters)
+
+			Convey("They should be sorted by number, not lexicographically", func() {
+				So(chapters[0].Name, ShouldEqual, "a")
+				So(chapters[1].Name, ShouldEqual, "b")
+				So(chapters[2].Name, ShouldEqual, "c")
+				So(chapters[3].Name, ShouldEqual, "d")
+			})
+		})
+
+		Convey("When NumberOrIndex is called on a chapter without number", func() {
+			So(chapters[3].NumberOrIndex(), ShouldEqual, "4")
+		})
+	})
+}
+
+func TestCompareChapters(t *testing.T) {
+	Convey("Given numbered and unnumbered chapters mixed", t, func() {
+		chapters := []*Chapter{
+			{Name: "extra", Index: 2},
+			{Name: "3", Number: "3", Index: 5},
+			{Name: "prologue", Index: 1},
+			{Name: "1", Number: "1", Index: 3},
+			{Name: "2", Number: "2", Index: 4},
+			{Name: "2 again", Number: "2", Index: 0},
+		}
+
+		Convey("When SortChapters is called", func() {
+			SortChapters(chapters)
+
+			Convey("Numbered chapters should go first and unnumbered ones should keep their index order", func() {
+				names := make([]string, len(chapters))
+				for i, chapter := range chapters {
+					names[i] = chapter.Name
+				}
+
+				So(names, ShouldResemble, []string{"1", "2 again", "2", "3", "prologue", "extra"})
+			})
+		})
+
+		Convey("Then the order should be transitive", func() {
+			for _, a := range chapters {
+				for _, b := range chapters {
+					for _, c := range chapters {
+						if CompareChapters(a, b) < 0 && CompareChapters(b, c) < 0 {
+							So(CompareChapters(a, c), ShouldBeLessThan, 0)
+						}
+					}
+				}
+			}
+		})
+	})
+}
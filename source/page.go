@@ -2,15 +2,18 @@ package source
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
-	"github.com/preetbiswas12/Kage/constant"
+	"github.com/preetbiswas12/Kage/key"
 	"github.com/preetbiswas12/Kage/log"
 	"github.com/preetbiswas12/Kage/network"
 	"github.com/preetbiswas12/Kage/util"
 	_ "image/gif"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Page represents a page in a chapter
@@ -29,20 +32,74 @@ type Page struct {
 	Chapter *Chapter `json:"-"`
 }
 
-func (p *Page) request() (*http.Request, error) {
-	req, err := http.NewRequest(http.MethodGet, p.URL, nil)
+// PageReport is the result of a page download.
+type PageReport struct {
+	// Success is true if the page was downloaded
+	Success bool
+	// Size of the downloaded page in bytes
+	Size uint64
+	// Duration of the download
+	Duration time.Duration
+	// Cached is true if the page was served from the server cache
+	Cached bool
+}
+
+// PageReporter is a Source that needs to know the results of page downloads,
+// e.g. to report them back to the image servers.
+type PageReporter interface {
+	Source
+	ReportPage(page *Page, report PageReport)
+}
+
+// PageDownloader is a Source that loads page contents by itself instead of requesting the page URL,
+// e.g. from the local files.
+type PageDownloader interface {
+	Source
+	// DownloadPage sets the page contents and size
+	DownloadPage(ctx context.Context, page *Page) error
+}
+
+// downloader returns the source of the page if it is a PageDownloader
+func (p *Page) downloader() (PageDownloader, bool) {
+	if p.Chapter == nil || p.Chapter.Manga == nil {
+		return nil, false
+	}
+
+	downloader, ok := p.Chapter.Source().(PageDownloader)
+	return downloader, ok
+}
+
+// report sends the download result to the source of the page if it is a PageReporter
+func (p *Page) report(report PageReport) {
+	if p.Chapter == nil || p.Chapter.Manga == nil {
+		return
+	}
+
+	if reporter, ok := p.Chapter.Source().(PageReporter); ok {
+		reporter.ReportPage(p, report)
+	}
+}
+
+func (p *Page) request(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
 
 	req.Header.Set("Referer", p.Chapter.URL)
-	req.Header.Set("User-Agent", constant.UserAgent)
+	req.Header.Set("User-Agent", network.UserAgent())
 	return req, nil
 }
 
 // Download Page contents.
 func (p *Page) Download() error {
+	return p.DownloadContext(context.Background())
+}
+
+// DownloadContext downloads Page contents.
+// The download is cancelled when the context is done or the configured page timeout is exceeded.
+func (p *Page) DownloadContext(ctx context.Context) (err error) {
 	if p.URL == "" {
 		log.Warnf("Page #%d has no URL", p.Index)
 		return nil
@@ -50,11 +107,41 @@ func (p *Page) Download() error {
 
 	log.Tracef("Downloading page #%d (%s)", p.Index, p.URL)
 
-	req, err := p.request()
+	if p.Chapter != nil && p.Chapter.Manga != nil {
+		ctx = withSource(ctx, p.Source())
+	}
+
+	if downloader, ok := p.downloader(); ok {
+		return downloader.DownloadPage(ctx, p)
+	}
+
+	ctx, cancel := withTimeout(ctx, key.NetworkPageTimeout)
+	defer cancel()
+
+	req, err := p.request(ctx)
 	if err != nil {
 		return err
 	}
 
+	var (
+		start  = time.Now()
+		cached bool
+	)
+
+	defer func() {
+		// cancelled downloads say nothing about the server
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		p.report(PageReport{
+			Success:  err == nil,
+			Size:     p.Size,
+			Duration: time.Since(start),
+			Cached:   cached,
+		})
+	}()
+
 	resp, err := network.Client.Do(req)
 	if err != nil {
 		log.Errorf("Network error downloading page #%d: %v", p.Index, err)
@@ -62,6 +149,7 @@ func (p *Page) Download() error {
 	}
 
 	defer util.Ignore(resp.Body.Close)
+	cached = strings.HasPrefix(resp.Header.Get("X-Cache"), "HIT")
 
 	if resp.StatusCode != http.StatusOK {
 		err = fmt.Errorf("http error for page #%d: %s", p.Index, resp.Status)
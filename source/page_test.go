@@ -1 +1,58 @@
 package source
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// reportingSource records the reported page downloads
+type reportingSource struct {
+	testSource
+	reports []PageReport
+}
+
+func (r *reportingSource) ReportPage(_ *Page, report PageReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestPage_DownloadReport(t *testing.T) {
+	Convey("Given a page of a source that wants download reports", t, func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/missing.png" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			w.Header().Set("X-Cache", "HIT")
+			_, _ = w.Write([]byte("image"))
+		}))
+		defer server.Close()
+
+		src := &reportingSource{}
+		chapter := &Chapter{Manga: &Manga{Source: src}}
+
+		Convey("When the page is downloaded", func() {
+			page := &Page{URL: server.URL + "/1.png", Chapter: chapter}
+			So(page.Download(), ShouldBeNil)
+
+			Convey("The success should be reported", func() {
+				So(src.reports, ShouldHaveLength, 1)
+				So(src.reports[0].Success, ShouldBeTrue)
+				So(src.reports[0].Cached, ShouldBeTrue)
+				So(src.reports[0].Size, ShouldEqual, 5)
+			})
+		})
+
+		Convey("When the page fails to download", func() {
+			page := &Page{URL: server.URL + "/missing.png", Chapter: chapter}
+			So(page.Download(), ShouldNotBeNil)
+
+			Convey("The failure should be reported", func() {
+				So(src.reports, ShouldHaveLength, 1)
+				So(src.reports[0].Success, ShouldBeFalse)
+			})
+		})
+	})
+}
@@ -0,0 +1,111 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"github.com/preetbiswas12/Kage/key"
+)
+
+// SearchPage is a single page of search results.
+type SearchPage struct {
+	// Mangas found on this page
+	Mangas []*Manga
+	// Next is a token to request the following page with.
+	// Empty if there are no more pages.
+	Next string
+}
+
+// PagedSearcher is a Source that can return search results page by page.
+// Empty token stands for the first page.
+type PagedSearcher interface {
+	Source
+	SearchPage(ctx context.Context, query, token string) (*SearchPage, error)
+}
+
+// SearchIterator iterates over search results of the source page by page.
+// Sources that do not implement PagedSearcher are returned as a single page.
+type SearchIterator struct {
+	source  Source
+	page    func(ctx context.Context, token string) (*SearchPage, error)
+	next    string
+	started bool
+	failed  bool
+	offset  int
+}
+
+// NewSearchIterator creates a new search iterator for the given query.
+func NewSearchIterator(src Source, query string) *SearchIterator {
+	return NewFilteredSearchIterator(src, query, nil)
+}
+
+// NewFilteredSearchIterator creates a new search iterator for the given query and filters.
+// Source must implement Filterable if any filters are given.
+func NewFilteredSearchIterator(src Source, query string, filters Filters) *SearchIterator {
+	return &SearchIterator{
+		source: src,
+		page: func(ctx context.Context, token string) (*SearchPage, error) {
+			if len(filters) > 0 {
+				s, ok := src.(Filterable)
+				if !ok {
+					return nil, fmt.Errorf("source %s does not support filters", src.Name())
+				}
+
+				return s.SearchFilteredPage(ctx, query, filters, token)
+			}
+
+			if s, ok := src.(PagedSearcher); ok {
+				return s.SearchPage(ctx, query, token)
+			}
+
+			mangas, err := Search(ctx, src, query)
+			if err != nil {
+				return nil, err
+			}
+
+			return &SearchPage{Mangas: mangas}, nil
+		},
+	}
+}
+
+// Source the iterator searches with.
+func (it *SearchIterator) Source() Source {
+	return it.source
+}
+
+// HasNext reports whether there are more pages to load.
+func (it *SearchIterator) HasNext() bool {
+	return !it.started || it.next != ""
+}
+
+// Failed reports whether the last page failed to load.
+func (it *SearchIterator) Failed() bool {
+	return it.failed
+}
+
+// Next loads the following page of results.
+// It returns nil with no error if there are no more pages.
+func (it *SearchIterator) Next(ctx context.Context) ([]*Manga, error) {
+	if !it.HasNext() {
+		return nil, nil
+	}
+
+	ctx, cancel := withTimeout(withSource(ctx, it.source), key.NetworkSearchTimeout)
+	defer cancel()
+
+	page, err := it.page(ctx, it.next)
+	it.failed = err != nil
+	if err != nil {
+		return nil, err
+	}
+
+	// keep indexes continuous across the pages
+	for i, manga := range page.Mangas {
+		manga.Index = uint16(it.offset + i)
+	}
+
+	it.started = true
+	it.next = page.Next
+	it.offset += len(page.Mangas)
+
+	return page.Mangas, nil
+}
@@ -0,0 +1,71 @@
+package source
+
+import (
+	"context"
+	. "github.com/smartystreets/goconvey/convey"
+	"strconv"
+	"testing"
+)
+
+type testPagedSource struct {
+	testSource
+	pages [][]string
+}
+
+func (t testPagedSource) SearchPage(_ context.Context, _, token string) (*SearchPage, error) {
+	var page int
+	if token != "" {
+		page, _ = strconv.Atoi(token)
+	}
+
+	result := &SearchPage{}
+	for _, name := range t.pages[page] {
+		result.Mangas = append(result.Mangas, &Manga{Name: name})
+	}
+
+	if page+1 < len(t.pages) {
+		result.Next = strconv.Itoa(page + 1)
+	}
+
+	return result, nil
+}
+
+func TestSearchIterator(t *testing.T) {
+	Convey("Given a source with paged search", t, func() {
+		src := testPagedSource{pages: [][]string{{"a", "b"}, {"c"}}}
+		it := NewSearchIterator(src, "test")
+
+		Convey("When all pages are loaded", func() {
+			var mangas []*Manga
+			for it.HasNext() {
+				page, err := it.Next(context.Background())
+				So(err, ShouldBeNil)
+				mangas = append(mangas, page...)
+			}
+
+			Convey("It should return mangas from every page", func() {
+				So(len(mangas), ShouldEqual, 3)
+				So(mangas[2].Name, ShouldEqual, "c")
+			})
+
+			Convey("It should keep indexes continuous", func() {
+				for i, manga := range mangas {
+					So(manga.Index, ShouldEqual, i)
+				}
+			})
+		})
+	})
+
+	Convey("Given a source without paged search", t, func() {
+		it := NewSearchIterator(&testSource{}, "test")
+
+		Convey("When the first page is loaded", func() {
+			_, err := it.Next(context.Background())
+
+			Convey("It should have no more pages", func() {
+				So(err, ShouldBeNil)
+				So(it.HasNext(), ShouldBeFalse)
+			})
+		})
+	})
+}
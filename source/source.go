@@ -1,5 +1,7 @@
 package source
 
+import "context"
+
 // Source is the interface that all sources must implement.
 type Source interface {
 	Name() string
@@ -8,3 +10,12 @@ type Source interface {
 	PagesOf(chapter *Chapter) ([]*Page, error)
 	ID() string
 }
+
+// ContextSource is a Source which operations can be cancelled
+// or bounded by a deadline with the given context.
+type ContextSource interface {
+	Source
+	SearchContext(ctx context.Context, query string) ([]*Manga, error)
+	ChaptersOfContext(ctx context.Context, manga *Manga) ([]*Chapter, error)
+	PagesOfContext(ctx context.Context, chapter *Chapter) ([]*Page, error)
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/preetbiswas12/Kage/source"
+)
+
+// mangaVariant is a variant of the merged manga shown in the manga sources list
+type mangaVariant struct {
+	manga *source.Manga
+}
+
+// mangaItems lists the found mangas, merged by title if the search is aggregated
+func (b *statefulBubble) mangaItems() []list.Item {
+	if !b.aggregateMangas {
+		items := make([]list.Item, len(b.foundMangas))
+		for i, m := range b.foundMangas {
+			items[i] = &listItem{internal: m}
+		}
+
+		return items
+	}
+
+	merged := source.Aggregate(b.searchQuery, b.selectedSources, b.foundMangas)
+	items := make([]list.Item, len(merged))
+	for i, m := range merged {
+		items[i] = &listItem{internal: m}
+	}
+
+	return items
+}
+
+// setMangaSourceItems lists the sources of the merged manga
+func (b *statefulBubble) setMangaSourceItems(merged *source.AggregatedManga) tea.Cmd {
+	items := make([]list.Item, len(merged.Mangas))
+	for i, m := range merged.Mangas {
+		items[i] = &listItem{internal: &mangaVariant{manga: m}}
+	}
+
+	b.mangaSourcesC.Title = merged.Name
+	return b.mangaSourcesC.SetItems(items)
+}
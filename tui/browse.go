@@ -0,0 +1,27 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/preetbiswas12/Kage/source"
+)
+
+// browseEntry is a browse list of the source shown in the browse list
+type browseEntry struct {
+	source source.Source
+	list   source.BrowseList
+}
+
+// setBrowseItems lists browse lists of the selected sources
+func (b *statefulBubble) setBrowseItems() tea.Cmd {
+	items := make([]list.Item, 0)
+	for _, s := range b.selectedSources {
+		for _, l := range source.BrowseListsOf(s) {
+			items = append(items, &listItem{
+				internal: &browseEntry{source: s, list: l},
+			})
+		}
+	}
+
+	return b.browseC.SetItems(items)
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -36,18 +37,30 @@ type statefulBubble struct {
 	// components
 	spinnerC         spinner.Model
 	inputC           textinput.Model
+	filterInputC     textinput.Model
 	scrapersInstallC list.Model
 	historyC         list.Model
 	sourcesC         list.Model
 	mangasC          list.Model
+	mangaSourcesC    list.Model
 	chaptersC        list.Model
 	anilistC         list.Model
+	filtersC         list.Model
+	browseC          list.Model
 	progressC        progress.Model
 	helpC            help.Model
 
 	selectedProviders map[*provider.Provider]struct{}
 	selectedSources   []source.Source
 	selectedManga     *source.Manga
+	searchIterators   []*source.SearchIterator
+	searchQuery       string
+	foundMangas       []*source.Manga
+	aggregateMangas   bool
+	searchFilters     source.Filters
+	filterDefinitions map[source.Source][]*source.Filter
+	editedFilter      *filterOption
+	loadingMore       bool
 	selectedChapters  map[*source.Chapter]struct{} // mathematical set
 
 	scrapersLoadedChannel       chan []*installer.Scraper
@@ -75,6 +88,9 @@ type statefulBubble struct {
 	succededChapters []*source.Chapter
 
 	searchSuggestion mo.Option[string]
+
+	// cancel stops the currently running search, loading or download
+	cancel context.CancelFunc
 }
 
 func (b *statefulBubble) raiseError(err error) {
@@ -118,6 +134,24 @@ func (b *statefulBubble) previousState() {
 	}
 }
 
+// operation returns a context for a new cancellable operation.
+// The previous operation is cancelled, if it is still running.
+func (b *statefulBubble) operation() context.Context {
+	b.cancelOperation()
+
+	var ctx context.Context
+	ctx, b.cancel = context.WithCancel(context.Background())
+	return ctx
+}
+
+// cancelOperation cancels the currently running operation, if any.
+func (b *statefulBubble) cancelOperation() {
+	if b.cancel != nil {
+		b.cancel()
+		b.cancel = nil
+	}
+}
+
 func (b *statefulBubble) resize(width, height int) {
 	x, y := paddingStyle.GetFrameSize()
 	xx, yy := listExtraPaddingStyle.GetFrameSize()
@@ -137,8 +171,14 @@ func (b *statefulBubble) resize(width, height int) {
 	b.sourcesC.SetSize(listWidth, listHeight)
 	b.sourcesC.Help.Width = listWidth
 
+	b.browseC.SetSize(listWidth, listHeight)
+	b.browseC.Help.Width = listWidth
+	b.filtersC.SetSize(listWidth, listHeight)
+	b.filtersC.Help.Width = listWidth
 	b.mangasC.SetSize(listWidth, listHeight)
 	b.mangasC.Help.Width = listWidth
+	b.mangaSourcesC.SetSize(listWidth, listHeight)
+	b.mangaSourcesC.Help.Width = listWidth
 
 	b.chaptersC.SetSize(listWidth, listHeight)
 	b.chaptersC.Help.Width = listWidth
@@ -155,12 +195,13 @@ func (b *statefulBubble) resize(width, height int) {
 
 func (b *statefulBubble) startLoading() tea.Cmd {
 	b.loading = true
-	return tea.Batch(b.mangasC.StartSpinner(), b.chaptersC.StartSpinner())
+	return tea.Batch(b.mangasC.StartSpinner(), b.mangaSourcesC.StartSpinner(), b.chaptersC.StartSpinner())
 }
 
 func (b *statefulBubble) stopLoading() tea.Cmd {
 	b.loading = false
 	b.mangasC.StopSpinner()
+	b.mangaSourcesC.StopSpinner()
 	b.chaptersC.StopSpinner()
 	return nil
 }
@@ -183,6 +224,7 @@ func newBubble() *statefulBubble {
 		errorChannel:                make(chan error),
 
 		selectedProviders:  make(map[*provider.Provider]struct{}),
+		searchFilters:      make(source.Filters),
 		selectedChapters:   make(map[*source.Chapter]struct{}),
 		chaptersToDownload: util.Stack[*source.Chapter]{},
 
@@ -236,6 +278,9 @@ func newBubble() *statefulBubble {
 	bubble.inputC.CharLimit = 60
 	bubble.inputC.Prompt = viper.GetString(key2.TUISearchPromptString)
 
+	bubble.filterInputC = textinput.New()
+	bubble.filterInputC.CharLimit = 60
+
 	bubble.progressC = progress.New(progress.WithDefaultGradient())
 
 	bubble.scrapersInstallC = makeList("Install Scrapers", true, &listOptions{
@@ -263,6 +308,13 @@ func newBubble() *statefulBubble {
 	})
 	bubble.mangasC.SetStatusBarItemName("manga", "mangas")
 
+	bubble.mangaSourcesC = makeList("Sources", true, &listOptions{
+		TitleStyle: mo.Some(
+			style.NewColored("#f2e8cf", "#6a994e").Padding(0, 1),
+		),
+	})
+	bubble.mangaSourcesC.SetStatusBarItemName("source", "sources")
+
 	bubble.chaptersC = makeList("Chapters", showURLs, &listOptions{
 		TitleStyle: mo.Some(
 			style.NewColored("#000814", color.Orange).Padding(0, 1),
@@ -277,6 +329,20 @@ func newBubble() *statefulBubble {
 	})
 	bubble.anilistC.SetStatusBarItemName("manga", "mangas")
 
+	bubble.filtersC = makeList("Search Filters", true, &listOptions{
+		TitleStyle: mo.Some(
+			style.NewColored("#edf2f4", "#8d99ae").Padding(0, 1),
+		),
+	})
+	bubble.filtersC.SetStatusBarItemName("filter", "filters")
+
+	bubble.browseC = makeList("Browse", true, &listOptions{
+		TitleStyle: mo.Some(
+			style.NewColored("#f1faee", "#457b9d").Padding(0, 1),
+		),
+	})
+	bubble.browseC.SetStatusBarItemName("list", "lists")
+
 	if w, h, err := util.TerminalSize(); err == nil {
 		bubble.resize(w, h)
 	}
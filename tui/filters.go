@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/preetbiswas12/Kage/log"
+	"github.com/preetbiswas12/Kage/source"
+	"github.com/samber/lo"
+	"sort"
+	"strings"
+)
+
+// filterOption is a single option of the search filter shown in the filters list.
+// Filters without predefined options are shown as a single item with the value entered by the user
+type filterOption struct {
+	filter *source.Filter
+	option source.FilterOption
+	// value entered for the filter without options
+	value string
+}
+
+// isInput reports whether the filter value is entered instead of chosen from the options
+func (f *filterOption) isInput() bool {
+	return len(f.filter.Options) == 0
+}
+
+// filtersLoadedMsg is sent when filters of the selected sources are loaded
+type filtersLoadedMsg struct {
+	definitions map[source.Source][]*source.Filter
+	options     []*filterOption
+}
+
+// loadFilters loads filters supported by the selected sources.
+// Filters that do not have predefined options are shown as input items
+func (b *statefulBubble) loadFilters() tea.Cmd {
+	return func() tea.Msg {
+		b.progressStatus = "Loading filters"
+
+		var (
+			definitionsOf = make(map[source.Source][]*source.Filter)
+			options       = make([]*filterOption, 0)
+			seen          = make(map[string]struct{})
+		)
+
+		for _, s := range b.selectedSources {
+			definitions, err := source.FiltersOf(s)
+			if err != nil {
+				log.Error(err)
+				return err
+			}
+
+			definitionsOf[s] = definitions
+
+			for _, definition := range definitions {
+				if len(definition.Options) == 0 {
+					if _, ok := seen[definition.Key]; !ok {
+						seen[definition.Key] = struct{}{}
+						options = append(options, &filterOption{filter: definition})
+					}
+
+					continue
+				}
+
+				for _, option := range definition.Options {
+					id := definition.Key + "=" + option.Value
+					if _, ok := seen[id]; ok {
+						continue
+					}
+
+					seen[id] = struct{}{}
+					options = append(options, &filterOption{filter: definition, option: option})
+				}
+			}
+		}
+
+		return filtersLoadedMsg{
+			definitions: definitionsOf,
+			options:     options,
+		}
+	}
+}
+
+// filtersFor returns the chosen filters supported by the source
+func (b *statefulBubble) filtersFor(s source.Source) source.Filters {
+	return b.searchFilters.For(b.filterDefinitions[s])
+}
+
+// toggleFilterOption selects or deselects the option.
+// Other options of the filter are deselected if it accepts only one value
+func (b *statefulBubble) toggleFilterOption(item *listItem) {
+	selected := item.internal.(*filterOption)
+	k, value := selected.filter.Key, selected.option.Value
+
+	if item.marked {
+		b.searchFilters[k] = lo.Without(b.searchFilters[k], value)
+		if len(b.searchFilters[k]) == 0 {
+			delete(b.searchFilters, k)
+		}
+
+		item.toggleMark()
+		return
+	}
+
+	if !selected.filter.Multiple {
+		delete(b.searchFilters, k)
+		for _, other := range b.filtersC.Items() {
+			other := other.(*listItem)
+			if other.internal.(*filterOption).filter.Key == k {
+				other.marked = false
+			}
+		}
+	}
+
+	b.searchFilters.Add(k, value)
+	item.toggleMark()
+}
+
+// editFilter starts entering the value of the filter without options
+func (b *statefulBubble) editFilter(option *filterOption) tea.Cmd {
+	b.editedFilter = option
+	b.filterInputC.Placeholder = option.filter.Name
+	b.filterInputC.SetValue(strings.Join(b.searchFilters[option.filter.Key], ", "))
+	b.filterInputC.CursorEnd()
+	return b.filterInputC.Focus()
+}
+
+// applyFilterInput sets the entered value of the edited filter.
+// Values of the filters that accept more than one are separated by commas
+func (b *statefulBubble) applyFilterInput() {
+	option := b.editedFilter
+	b.stopFilterInput()
+
+	k := option.filter.Key
+	delete(b.searchFilters, k)
+
+	values := []string{b.filterInputC.Value()}
+	if option.filter.Multiple {
+		values = strings.Split(b.filterInputC.Value(), ",")
+	}
+
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			b.searchFilters.Add(k, value)
+		}
+	}
+
+	option.value = strings.Join(b.searchFilters[k], ", ")
+	for _, item := range b.filtersC.Items() {
+		if item := item.(*listItem); item.internal == option {
+			item.marked = option.value != ""
+		}
+	}
+}
+
+// stopFilterInput stops entering the filter value
+func (b *statefulBubble) stopFilterInput() {
+	b.editedFilter = nil
+	b.filterInputC.Blur()
+}
+
+// clearFilters deselects all the filters
+func (b *statefulBubble) clearFilters() {
+	b.searchFilters = make(source.Filters)
+	for _, item := range b.filtersC.Items() {
+		item := item.(*listItem)
+		item.marked = false
+		item.internal.(*filterOption).value = ""
+	}
+}
+
+// filtersSummary returns a short description of chosen filters
+func (b *statefulBubble) filtersSummary() string {
+	keys := lo.Keys(b.searchFilters)
+	sort.Strings(keys)
+
+	names := make(map[string]string)
+	for _, item := range b.filtersC.Items() {
+		option := item.(*listItem).internal.(*filterOption)
+		names[option.filter.Key+"="+option.option.Value] = option.option.Name
+	}
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		values := lo.Map(b.searchFilters[k], func(value string, _ int) string {
+			if name, ok := names[k+"="+value]; ok {
+				return name
+			}
+
+			return value
+		})
+
+		parts[i] = fmt.Sprintf("%s: %s", k, strings.Join(values, ", "))
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func (b *statefulBubble) setFilterItems(options []*filterOption) tea.Cmd {
+	items := make([]list.Item, len(options))
+	for i, option := range options {
+		marked := lo.Contains(b.searchFilters[option.filter.Key], option.option.Value)
+		if option.isInput() {
+			option.value = strings.Join(b.searchFilters[option.filter.Key], ", ")
+			marked = option.value != ""
+		}
+
+		items[i] = &listItem{
+			internal: option,
+			marked:   marked,
+		}
+	}
+
+	return b.filtersC.SetItems(items)
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,6 +15,7 @@ import (
 	"github.com/preetbiswas12/Kage/source"
 	"github.com/preetbiswas12/Kage/style"
 	"github.com/preetbiswas12/Kage/util"
+	"github.com/samber/lo"
 	"github.com/spf13/viper"
 	"golang.org/x/exp/slices"
 	"strings"
@@ -142,32 +144,64 @@ func (b *statefulBubble) waitForSourcesLoaded() tea.Cmd {
 }
 
 func (b *statefulBubble) searchManga(query string) tea.Cmd {
-	return func() tea.Msg {
-		log.Info("searching for " + query)
-		b.progressStatus = fmt.Sprintf("Searching among %s", util.Quantify(len(b.selectedSources), "source", "sources"))
+	log.Info("searching for " + query)
 
-		var mangas = make([]*source.Manga, 0)
+	iterators := make([]*source.SearchIterator, len(b.selectedSources))
+	for i, s := range b.selectedSources {
+		iterators[i] = source.NewFilteredSearchIterator(s, query, b.filtersFor(s))
+	}
 
-		wg := sync.WaitGroup{}
-		wg.Add(len(b.selectedSources))
-		for _, s := range b.selectedSources {
-			go func(s source.Source) {
-				defer wg.Done()
-				sourceMangas, err := s.Search(query)
+	b.searchQuery = query
+	b.aggregateMangas = viper.GetBool(key.SearchAggregate) && len(b.selectedSources) > 1
 
-				if err != nil {
-					log.Error(err)
-					b.errorChannel <- err
-				}
+	return b.loadMangas(iterators, fmt.Sprintf("Searching among %s", util.Quantify(len(b.selectedSources), "source", "sources")))
+}
+
+func (b *statefulBubble) browseManga(entry *browseEntry) tea.Cmd {
+	log.Infof("browsing %s mangas of %s", entry.list, entry.source.Name())
+
+	it, err := source.NewBrowseIterator(entry.source, entry.list)
+	if err != nil {
+		return func() tea.Msg {
+			log.Error(err)
+			b.errorChannel <- err
+			return nil
+		}
+	}
+
+	// browse lists have no query to rank the merged mangas by
+	b.searchQuery = ""
+	b.aggregateMangas = false
+	return b.loadMangas([]*source.SearchIterator{it}, fmt.Sprintf("Browsing %s mangas", entry.list))
+}
+
+// loadMangas loads the first page of mangas from each iterator.
+// Iterators are kept to load more mangas later
+func (b *statefulBubble) loadMangas(iterators []*source.SearchIterator, status string) tea.Cmd {
+	ctx := b.operation()
+	b.searchIterators = iterators
+
+	return func() tea.Msg {
+		b.progressStatus = status
 
-				log.Infof("found %s from source %s", util.Quantify(len(sourceMangas), "manga", "mangas"), s.Name())
-				mangas = append(mangas, sourceMangas...)
-			}(s)
+		mangas, errs := source.SearchAll(ctx, iterators)
+
+		if err := ctx.Err(); err != nil {
+			b.errorChannel <- err
+			return nil
 		}
 
-		wg.Wait()
+		for _, err := range errs {
+			log.Error(err)
+		}
 
-		log.Infof("found %d mangas from %d sources", len(mangas), len(b.selectedSources))
+		// a failed source is only reported if there is nothing to show from the others
+		if len(errs) > 0 && len(errs) == len(iterators) {
+			b.errorChannel <- errs[0]
+			return nil
+		}
+
+		log.Infof("found %d mangas from %d sources", len(mangas), len(iterators))
 
 		b.foundMangasChannel <- mangas
 
@@ -175,6 +209,56 @@ func (b *statefulBubble) searchManga(query string) tea.Cmd {
 	}
 }
 
+// moreMangasMsg is sent when the next pages of search results are loaded
+type moreMangasMsg struct {
+	mangas []*source.Manga
+	// failed are the names of the sources that failed to load more
+	failed []string
+}
+
+// hasMoreMangas reports whether any of the sources has more search results to load
+func (b *statefulBubble) hasMoreMangas() bool {
+	return lo.SomeBy(b.searchIterators, func(it *source.SearchIterator) bool {
+		return it.HasNext()
+	})
+}
+
+// loadMoreMangas loads the next page of search results from every source that has one
+func (b *statefulBubble) loadMoreMangas() tea.Cmd {
+	ctx := b.operation()
+	iterators := lo.Filter(b.searchIterators, func(it *source.SearchIterator, _ int) bool {
+		return it.HasNext()
+	})
+
+	return func() tea.Msg {
+		// results keep the order of the sources, same as the first page
+		mangas, errs := source.SearchAll(ctx, iterators)
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		for _, err := range errs {
+			log.Error(err)
+		}
+
+		if len(errs) > 0 && len(mangas) == 0 {
+			return errs[0]
+		}
+
+		failed := make([]string, 0, len(errs))
+		for _, err := range errs {
+			var searchErr *source.SearchError
+			if errors.As(err, &searchErr) {
+				failed = append(failed, searchErr.Source)
+			}
+		}
+
+		log.Infof("loaded %s more from %s", util.Quantify(len(mangas), "manga", "mangas"), util.Quantify(len(iterators), "source", "sources"))
+		return moreMangasMsg{mangas: mangas, failed: failed}
+	}
+}
+
 func (b *statefulBubble) waitForMangas() tea.Cmd {
 	return func() tea.Msg {
 		select {
@@ -188,9 +272,14 @@ func (b *statefulBubble) waitForMangas() tea.Cmd {
 }
 
 func (b *statefulBubble) getChapters(manga *source.Manga) tea.Cmd {
+	ctx := b.operation()
 	return func() tea.Msg {
 		log.Info("getting chapters of " + manga.Name)
-		chapters, err := manga.Source.ChaptersOf(manga)
+		chapters, err := source.ChaptersOf(ctx, manga.Source, manga)
+		if err == nil {
+			chapters, err = source.DedupChapters(manga, chapters)
+		}
+
 		if err != nil {
 			log.Error(err)
 			b.errorChannel <- err
@@ -216,9 +305,10 @@ func (b *statefulBubble) waitForChapters() tea.Cmd {
 }
 
 func (b *statefulBubble) readChapter(chapter *source.Chapter) tea.Cmd {
+	ctx := b.operation()
 	return func() tea.Msg {
 		b.currentDownloadingChapter = chapter
-		err := downloader.Read(chapter, func(s string) {
+		err := downloader.ReadContext(ctx, chapter, func(s string) {
 			b.progressStatus = s
 		})
 
@@ -245,14 +335,15 @@ func (b *statefulBubble) waitForChapterRead() tea.Cmd {
 }
 
 func (b *statefulBubble) downloadChapter(chapter *source.Chapter) tea.Cmd {
+	ctx := b.operation()
 	return func() tea.Msg {
 		b.currentDownloadingChapter = chapter
-		_, err := downloader.Download(chapter, func(s string) {
+		_, err := downloader.DownloadContext(ctx, chapter, func(s string) {
 			b.progressStatus = s
 		})
 
 		if err != nil {
-			if viper.GetBool(key.DownloaderStopOnError) {
+			if viper.GetBool(key.DownloaderStopOnError) || ctx.Err() != nil {
 				b.errorChannel <- err
 			} else {
 				b.failedChapters = append(b.failedChapters, chapter)
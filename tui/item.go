@@ -9,6 +9,7 @@ import (
 	"github.com/preetbiswas12/Kage/provider"
 	"github.com/preetbiswas12/Kage/source"
 	"github.com/preetbiswas12/Kage/style"
+	"github.com/preetbiswas12/Kage/util"
 	"strings"
 )
 
@@ -29,6 +30,8 @@ func (t *listItem) getMark() string {
 		return icon.Get(icon.Link)
 	case *provider.Provider:
 		return icon.Get(icon.Search)
+	case *filterOption:
+		return style.Bold(icon.Get(icon.Mark))
 	default:
 		return ""
 	}
@@ -66,9 +69,26 @@ func (t *listItem) Title() (title string) {
 func (t *listItem) Description() (description string) {
 	switch e := t.internal.(type) {
 	case *source.Chapter:
-		description = e.URL
+		var details []string
+		if len(e.Groups) > 0 {
+			details = append(details, strings.Join(e.Groups, ", "))
+		}
+
+		if e.Language != "" {
+			details = append(details, e.Language)
+		}
+
+		if !e.PublishedAt.IsZero() {
+			details = append(details, e.PublishedAt.Format("2006-01-02"))
+		}
+
+		description = strings.Join(append(details, e.URL), " • ")
 	case *source.Manga:
 		description = e.URL
+	case *source.AggregatedManga:
+		description = strings.Join(e.Sources(), ", ")
+	case *mangaVariant:
+		description = fmt.Sprintf("%s • %s", e.manga.Name, e.manga.URL)
 	case *installer.Scraper:
 		description = e.GithubURL()
 	case *history.SavedChapter:
@@ -88,6 +108,19 @@ func (t *listItem) Description() (description string) {
 		description = sb.String()
 	case *anilist.Manga:
 		description = e.SiteURL
+	case *browseEntry:
+		description = e.source.Name()
+	case *filterOption:
+		switch {
+		case e.isInput() && e.filter.Multiple:
+			description = "Any values separated by commas"
+		case e.isInput():
+			description = "Any value"
+		case e.filter.Multiple:
+			description = "Multiple values allowed"
+		default:
+			description = "Single value"
+		}
 	}
 
 	return
@@ -99,6 +132,10 @@ func (t *listItem) FilterValue() string {
 		return e.Name
 	case *source.Manga:
 		return e.Name
+	case *source.AggregatedManga:
+		return e.Name
+	case *mangaVariant:
+		return e.manga.Source.Name()
 	case *history.SavedChapter:
 		return e.MangaName
 	case *anilist.Manga:
@@ -107,6 +144,19 @@ func (t *listItem) FilterValue() string {
 		return e.Name
 	case *installer.Scraper:
 		return e.Name
+	case *filterOption:
+		if e.isInput() {
+			value := e.value
+			if value == "" {
+				value = "any"
+			}
+
+			return fmt.Sprintf("%s: %s", e.filter.Name, value)
+		}
+
+		return fmt.Sprintf("%s: %s", e.filter.Name, e.option.Name)
+	case *browseEntry:
+		return fmt.Sprintf("%s mangas", util.Capitalize(string(e.list)))
 	default:
 		return ""
 	}
@@ -13,12 +13,15 @@ type statefulKeymap struct {
 	quit, forceQuit,
 	selectOne, selectAll, selectVolume, clearSelection,
 	acceptSearchSuggestion,
+	searchFilters,
+	browse,
 	anilistSelect,
 	remove,
 	redownloadFailed,
 	confirm,
 	openURL,
 	read,
+	loadMore,
 	openFolder,
 	back,
 	filter,
@@ -77,10 +80,22 @@ func newStatefulKeymap() *statefulKeymap {
 			keys("r"),
 			help(style.Fg(color.Orange)("r"), style.Fg(color.Orange)("read")),
 		),
+		loadMore: k(
+			keys("m"),
+			help("m", "load more"),
+		),
 		acceptSearchSuggestion: k(
 			keys("tab"),
 			help("tab", "accept search suggestion"),
 		),
+		searchFilters: k(
+			keys("ctrl+f"),
+			help("ctrl+f", "filters"),
+		),
+		browse: k(
+			keys("ctrl+b"),
+			help("ctrl+b", "browse"),
+		),
 		redownloadFailed: k(
 			keys("r"),
 			help("r", "redownload failed"),
@@ -155,9 +170,16 @@ func (k *statefulKeymap) help() ([]key.Binding, []key.Binding) {
 		search := withDescription(k.confirm, "search with selected")
 		return h(k.selectOne, k.selectAll, search), h(k.selectOne, k.selectAll, k.clearSelection, search)
 	case searchState:
-		return to2(h(k.confirm, k.acceptSearchSuggestion, k.forceQuit))
+		return to2(h(k.confirm, k.acceptSearchSuggestion, k.searchFilters, k.browse, k.forceQuit))
+	case browseState:
+		return to2(h(k.confirm, k.back))
+	case filtersState:
+		done := withDescription(k.confirm, "done")
+		return h(k.selectOne, done, k.back), h(k.selectOne, k.clearSelection, done, k.back)
 	case mangasState:
-		return to2(h(k.confirm, k.back, k.openURL))
+		return h(k.confirm, k.back, k.loadMore), h(k.confirm, k.back, k.openURL, k.loadMore)
+	case mangaSourcesState:
+		return to2(h(k.confirm, k.openURL, k.back))
 	case chaptersState:
 		download := withDescription(k.confirm, "download selected")
 		return h(k.read, k.selectOne, k.selectAll, download, k.back), h(k.read, k.selectOne, k.selectAll, k.clearSelection, k.openURL, download, k.selectVolume, k.anilistSelect, k.back)
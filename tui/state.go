@@ -10,7 +10,10 @@ const (
 	historyState
 	sourcesState
 	searchState
+	filtersState
+	browseState
 	mangasState
+	mangaSourcesState
 	chaptersState
 	anilistSelectState
 	confirmState
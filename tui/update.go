@@ -1,7 +1,10 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -30,12 +33,20 @@ func (b *statefulBubble) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case error:
+		b.loadingMore = false
+
+		// cancelled operations were abandoned by the user on purpose
+		if errors.Is(msg, context.Canceled) {
+			return b, nil
+		}
+
 		b.raiseError(msg)
 	case tea.WindowSizeMsg:
 		b.resize(msg.Width, msg.Height)
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, b.keymap.forceQuit):
+			b.cancelOperation()
 			return b, tea.Quit
 		case key.Matches(msg, b.keymap.back):
 			onListBack := func(l *list.Model) tea.Cmd {
@@ -46,6 +57,8 @@ func (b *statefulBubble) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			switch b.state {
+			case loadingState, readState, downloadState:
+				b.cancelOperation()
 			case searchState:
 				b.inputC.SetValue("")
 			case chaptersState:
@@ -70,6 +83,13 @@ func (b *statefulBubble) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				cmd = onListBack(&b.mangasC)
+			case mangaSourcesState:
+				if b.mangaSourcesC.FilterState() != list.Unfiltered {
+					b.mangaSourcesC, cmd = b.mangaSourcesC.Update(msg)
+					return b, cmd
+				}
+
+				cmd = onListBack(&b.mangaSourcesC)
 			case historyState:
 				if b.historyC.FilterState() != list.Unfiltered {
 					b.historyC, cmd = b.historyC.Update(msg)
@@ -84,6 +104,25 @@ func (b *statefulBubble) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				cmd = onListBack(&b.sourcesC)
+			case filtersState:
+				if b.editedFilter != nil {
+					b.stopFilterInput()
+					return b, nil
+				}
+
+				if b.filtersC.FilterState() != list.Unfiltered {
+					b.filtersC, cmd = b.filtersC.Update(msg)
+					return b, cmd
+				}
+
+				cmd = onListBack(&b.filtersC)
+			case browseState:
+				if b.browseC.FilterState() != list.Unfiltered {
+					b.browseC, cmd = b.browseC.Update(msg)
+					return b, cmd
+				}
+
+				cmd = onListBack(&b.browseC)
 			case scrapersInstallState:
 				if b.scrapersInstallC.FilterState() != list.Unfiltered {
 					b.scrapersInstallC, cmd = b.scrapersInstallC.Update(msg)
@@ -112,8 +151,14 @@ func (b *statefulBubble) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return b.updateSources(msg)
 	case searchState:
 		return b.updateSearch(msg)
+	case filtersState:
+		return b.updateFilters(msg)
+	case browseState:
+		return b.updateBrowse(msg)
 	case mangasState:
 		return b.updateMangas(msg)
+	case mangaSourcesState:
+		return b.updateMangaSources(msg)
 	case chaptersState:
 		return b.updateChapters(msg)
 	case anilistSelectState:
@@ -237,12 +282,8 @@ func (b *statefulBubble) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 		b.scrapersInstallC.NewStatusMessage(fmt.Sprintf("Installed %s", msg.Name))
 		return b, b.stopLoading()
 	case []*source.Manga:
-		items := make([]list.Item, len(msg))
-		for i, m := range msg {
-			items[i] = &listItem{internal: m}
-		}
-
-		cmds = append(cmds, b.mangasC.SetItems(items))
+		b.foundMangas = msg
+		cmds = append(cmds, b.mangasC.SetItems(b.mangaItems()))
 		b.newState(mangasState)
 		b.stopLoading()
 	case []*source.Chapter:
@@ -253,9 +294,19 @@ func (b *statefulBubble) updateLoading(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return msg
 			})
 		}
+	case filtersLoadedMsg:
+		b.filterDefinitions = msg.definitions
+		b.newState(filtersState)
+		b.stopLoading()
+		cmds = append(cmds, b.setFilterItems(msg.options))
 	case []source.Source:
 		b.selectedSources = msg
 
+		// filters depend on the selected sources
+		b.searchFilters = make(source.Filters)
+		b.filterDefinitions = nil
+		cmds = append(cmds, b.filtersC.SetItems(nil))
+
 		if b.statesHistory.Peek() == historyState {
 			b.newState(historyState)
 			b.stopLoading()
@@ -426,6 +477,17 @@ func (b *statefulBubble) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 			b.newState(loadingState)
 			go query.Remember(b.inputC.Value(), 1)
 			return b, tea.Batch(b.searchManga(b.inputC.Value()), b.waitForMangas(), b.spinnerC.Tick)
+		case key.Matches(msg, b.keymap.searchFilters):
+			if b.filterDefinitions == nil {
+				b.newState(loadingState)
+				return b, tea.Batch(b.startLoading(), b.loadFilters(), b.spinnerC.Tick)
+			}
+
+			b.newState(filtersState)
+			return b, nil
+		case key.Matches(msg, b.keymap.browse):
+			b.newState(browseState)
+			return b, b.setBrowseItems()
 		case key.Matches(msg, b.keymap.acceptSearchSuggestion) && b.searchSuggestion.IsPresent():
 			b.inputC.SetValue(b.searchSuggestion.MustGet())
 			b.searchSuggestion = mo.None[string]()
@@ -449,6 +511,70 @@ func (b *statefulBubble) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return b, cmd
 }
 
+func (b *statefulBubble) updateFilters(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	if b.editedFilter != nil {
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, b.keymap.confirm) {
+			b.applyFilterInput()
+			return b, nil
+		}
+
+		b.filterInputC, cmd = b.filterInputC.Update(msg)
+		return b, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case b.filtersC.FilterState() == list.Filtering:
+			break
+		case key.Matches(msg, b.keymap.selectOne):
+			if b.filtersC.SelectedItem() == nil {
+				break
+			}
+
+			item := b.filtersC.SelectedItem().(*listItem)
+			if option := item.internal.(*filterOption); option.isInput() {
+				return b, b.editFilter(option)
+			}
+
+			b.toggleFilterOption(item)
+		case key.Matches(msg, b.keymap.clearSelection):
+			b.clearFilters()
+		case key.Matches(msg, b.keymap.confirm):
+			b.previousState()
+			return b, nil
+		}
+	}
+
+	b.filtersC, cmd = b.filtersC.Update(msg)
+	return b, cmd
+}
+
+func (b *statefulBubble) updateBrowse(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case b.browseC.FilterState() == list.Filtering:
+			break
+		case key.Matches(msg, b.keymap.confirm, b.keymap.selectOne):
+			if b.browseC.SelectedItem() == nil {
+				break
+			}
+
+			entry := b.browseC.SelectedItem().(*listItem).internal.(*browseEntry)
+			b.newState(loadingState)
+			return b, tea.Batch(b.startLoading(), b.browseManga(entry), b.waitForMangas(), b.spinnerC.Tick)
+		}
+	}
+
+	b.browseC, cmd = b.browseC.Update(msg)
+	return b, cmd
+}
+
 func (b *statefulBubble) updateMangas(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -462,7 +588,20 @@ func (b *statefulBubble) updateMangas(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 
-			m, _ := b.mangasC.SelectedItem().(*listItem).internal.(*source.Manga)
+			var m *source.Manga
+			switch e := b.mangasC.SelectedItem().(*listItem).internal.(type) {
+			case *source.Manga:
+				m = e
+			case *source.AggregatedManga:
+				// let the user pick the source if the manga was found in several
+				if len(e.Mangas) > 1 {
+					b.newState(mangaSourcesState)
+					return b, b.setMangaSourceItems(e)
+				}
+
+				m = e.Mangas[0]
+			}
+
 			b.selectedManga = m
 			go query.Remember(m.Name, 2)
 			return b, tea.Batch(b.getChapters(m), b.waitForChapters(), b.startLoading())
@@ -471,40 +610,125 @@ func (b *statefulBubble) updateMangas(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 
-			m, _ := b.mangasC.SelectedItem().(*listItem).internal.(*source.Manga)
-			err := open.Start(m.URL)
+			var url string
+			switch e := b.mangasC.SelectedItem().(*listItem).internal.(type) {
+			case *source.Manga:
+				url = e.URL
+			case *source.AggregatedManga:
+				url = e.Mangas[0].URL
+			}
+
+			err := open.Start(url)
 			if err != nil {
 				b.raiseError(err)
 			}
-		}
-	case []*source.Chapter:
-		items := make([]list.Item, len(msg))
+		case key.Matches(msg, b.keymap.loadMore):
+			if b.loadingMore {
+				break
+			}
 
-		if viper.GetBool(key2.TUIReverseChapters) {
-			for i, c := range msg {
-				items[len(msg)-i-1] = &listItem{internal: c}
+			if !b.hasMoreMangas() {
+				return b, b.mangasC.NewStatusMessage("No more results")
 			}
+
+			b.loadingMore = true
+			return b, tea.Batch(b.loadMoreMangas(), b.mangasC.StartSpinner())
+		}
+	case moreMangasMsg:
+		b.loadingMore = false
+		b.mangasC.StopSpinner()
+
+		b.foundMangas = append(b.foundMangas, msg.mangas...)
+
+		var items []list.Item
+		if b.aggregateMangas {
+			// merged mangas are ranked again with the new ones
+			items = b.mangaItems()
 		} else {
-			for i, c := range msg {
-				items[i] = &listItem{internal: c}
+			items = b.mangasC.Items()
+			for _, m := range msg.mangas {
+				items = append(items, &listItem{internal: m})
 			}
 		}
 
-		cmd = b.chaptersC.SetItems(items)
-		b.newState(chaptersState)
-		b.stopLoading()
-
-		if viper.GetBool(key2.AnilistLinkOnMangaSelect) {
-			return b, tea.Batch(cmd, b.fetchAndSetAnilist(b.selectedManga), b.waitForAnilistFetchAndSet())
+		status := fmt.Sprintf("Loaded %s", util.Quantify(len(msg.mangas), "more manga", "more mangas"))
+		if len(msg.failed) > 0 {
+			status += style.Fg(color.Red)(fmt.Sprintf(", failed to load from %s", strings.Join(msg.failed, ", ")))
 		}
 
-		return b, cmd
+		return b, tea.Batch(
+			b.mangasC.SetItems(items),
+			b.mangasC.NewStatusMessage(status),
+		)
+	case []*source.Chapter:
+		return b, b.showChapters(msg)
 	}
 
 	b.mangasC, cmd = b.mangasC.Update(msg)
 	return b, cmd
 }
 
+func (b *statefulBubble) updateMangaSources(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case b.mangaSourcesC.FilterState() == list.Filtering:
+			break
+		case key.Matches(msg, b.keymap.confirm, b.keymap.selectOne):
+			if b.mangaSourcesC.SelectedItem() == nil {
+				break
+			}
+
+			m := b.mangaSourcesC.SelectedItem().(*listItem).internal.(*mangaVariant).manga
+			b.selectedManga = m
+			go query.Remember(m.Name, 2)
+			return b, tea.Batch(b.getChapters(m), b.waitForChapters(), b.startLoading())
+		case key.Matches(msg, b.keymap.openURL):
+			if b.mangaSourcesC.SelectedItem() == nil {
+				break
+			}
+
+			m := b.mangaSourcesC.SelectedItem().(*listItem).internal.(*mangaVariant).manga
+			err := open.Start(m.URL)
+			if err != nil {
+				b.raiseError(err)
+			}
+		}
+	case []*source.Chapter:
+		return b, b.showChapters(msg)
+	}
+
+	b.mangaSourcesC, cmd = b.mangaSourcesC.Update(msg)
+	return b, cmd
+}
+
+// showChapters lists the chapters of the selected manga
+func (b *statefulBubble) showChapters(chapters []*source.Chapter) tea.Cmd {
+	items := make([]list.Item, len(chapters))
+
+	if viper.GetBool(key2.TUIReverseChapters) {
+		for i, c := range chapters {
+			items[len(chapters)-i-1] = &listItem{internal: c}
+		}
+	} else {
+		for i, c := range chapters {
+			items[i] = &listItem{internal: c}
+		}
+	}
+
+	cmd := b.chaptersC.SetItems(items)
+	b.newState(chaptersState)
+	b.stopLoading()
+
+	if viper.GetBool(key2.AnilistLinkOnMangaSelect) {
+		return tea.Batch(cmd, b.fetchAndSetAnilist(b.selectedManga), b.waitForAnilistFetchAndSet())
+	}
+
+	return cmd
+}
+
 func (b *statefulBubble) updateChapters(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -665,12 +889,7 @@ func (b *statefulBubble) updateConfirm(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, b.keymap.confirm):
 			chapters := lo.Keys(b.selectedChapters)
 			slices.SortFunc(chapters, func(a, b *source.Chapter) int {
-				if a.Index > b.Index {
-					return -1
-				} else if a.Index < b.Index {
-					return 1
-				}
-				return 0
+				return source.CompareChapters(b, a)
 			})
 
 			for _, chapter := range chapters {
@@ -31,8 +31,14 @@ func (b *statefulBubble) View() string {
 		return b.viewSources()
 	case searchState:
 		return b.viewSearch()
+	case filtersState:
+		return b.viewFilters()
+	case browseState:
+		return b.viewBrowse()
 	case mangasState:
 		return b.viewMangas()
+	case mangaSourcesState:
+		return b.viewMangaSources()
 	case chaptersState:
 		return b.viewChapters()
 	case anilistSelectState:
@@ -104,16 +110,51 @@ func (b *statefulBubble) viewSearch() string {
 		)
 	}
 
+	if len(b.searchFilters) > 0 {
+		lines = append(
+			lines,
+			"",
+			fmt.Sprintf("Filters %s", style.Fg(color.Purple)(b.filtersSummary())),
+		)
+	}
+
 	return b.renderLines(
 		true,
 		lines,
 	)
 }
 
+func (b *statefulBubble) viewFilters() string {
+	if b.editedFilter != nil {
+		hint := "Enter the value"
+		if b.editedFilter.filter.Multiple {
+			hint = "Enter the values separated by commas"
+		}
+
+		return b.renderLines(true, []string{
+			style.Title(b.editedFilter.filter.Name),
+			"",
+			b.filterInputC.View(),
+			"",
+			style.Faint(hint + ", leave empty to not filter"),
+		})
+	}
+
+	return listExtraPaddingStyle.Render(b.filtersC.View())
+}
+
+func (b *statefulBubble) viewBrowse() string {
+	return listExtraPaddingStyle.Render(b.browseC.View())
+}
+
 func (b *statefulBubble) viewMangas() string {
 	return listExtraPaddingStyle.Render(b.mangasC.View())
 }
 
+func (b *statefulBubble) viewMangaSources() string {
+	return listExtraPaddingStyle.Render(b.mangaSourcesC.View())
+}
+
 func (b *statefulBubble) viewChapters() string {
 	return listExtraPaddingStyle.Render(b.chaptersC.View())
 }
@@ -29,7 +29,7 @@ func Metadata(mangaPath string) error {
 	}
 
 	// will set new metadata from anilist
-	err = manga.PopulateMetadata(func(string) {})
+	err = manga.PopulateAnilistMetadata(func(string) {})
 	if err != nil {
 		log.Error()
 		return err
@@ -57,10 +57,11 @@ func Metadata(mangaPath string) error {
 		}
 
 		chap := &source.Chapter{
-			Name:  comicInfo.Title,
-			Manga: manga,
-			URL:   comicInfo.Web,
-			Index: uint16(comicInfo.Number),
+			Name:   comicInfo.Title,
+			Manga:  manga,
+			URL:    comicInfo.Web,
+			Number: source.NormalizeChapterNumber(comicInfo.Number),
+			Index:  uint16(len(manga.Chapters) + 1),
 		}
 		manga.Chapters = append(manga.Chapters, chap)
 		chaptersPaths[chap] = chapter.path
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Zip creates the archive of the files, given by their names and contents.
+// Files are written in the order of their names.
+func Zip(files map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+
+	names := maps.Keys(files)
+	slices.Sort(names)
+
+	for _, name := range names {
+		w, err := writer.Create(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = w.Write([]byte(files[name])); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
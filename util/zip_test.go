@@ -0,0 +1,31 @@
+package util
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"io"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	Convey("Given files with their contents", t, func() {
+		files := map[string]string{"2.jpg": "second", "1.jpg": "first"}
+
+		Convey("When zipping them", func() {
+			data, err := Zip(files)
+
+			Convey("Then the archive should contain the files in the order of their names", func() {
+				So(err, ShouldBeNil)
+
+				reader := lo.Must(zip.NewReader(bytes.NewReader(data), int64(len(data))))
+				So(reader.File, ShouldHaveLength, 2)
+				So(reader.File[0].Name, ShouldEqual, "1.jpg")
+
+				contents := lo.Must(io.ReadAll(lo.Must(reader.File[1].Open())))
+				So(string(contents), ShouldEqual, "second")
+			})
+		})
+	})
+}
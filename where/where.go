@@ -43,6 +43,11 @@ func AnilistBinds() string {
 	return filepath.Join(Config(), "anilist.json")
 }
 
+// Cookies path to the file
+func Cookies() string {
+	return filepath.Join(Config(), "cookies.json")
+}
+
 // Logs path
 // Will create the directory if it doesn't exist
 func Logs() string {